@@ -0,0 +1,28 @@
+package collections
+
+import "fmt"
+
+// Batched operations shared by Stack and Queue.
+
+// checkBatchSize returns an error if n is not a valid number of elements to
+// operate on in a collection of the given type and size.
+func checkBatchSize(typ string, n, size int) error {
+	if n < 0 || n > size {
+		return fmt.Errorf("count %d out of bounds for %s (size %d)", n, typ, size)
+	}
+	return nil
+}
+
+// copyBatch returns a List containing a copy of elems, in reverse order if
+// reverse is true.
+func copyBatch[T any](elems []T, reverse bool) *List[T] {
+	batch := make(List[T], len(elems))
+	if reverse {
+		for i, t := range elems {
+			batch[len(elems)-1-i] = t
+		}
+	} else {
+		copy(batch, elems)
+	}
+	return &batch
+}
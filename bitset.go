@@ -0,0 +1,352 @@
+package collections
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// BitSet is an implementation of a set of non-negative integers using a
+// slice of bits. It is much more compact than a Set[int] when the elements
+// are small and densely packed.
+//
+// Methods which add elements panic if given a negative integer.
+type BitSet struct {
+	words []uint64
+}
+
+const wordSize = 64
+
+// String returns a string representation of this BitSet.
+func (b *BitSet) String() string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		if sb.Len() > 1 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%d", i)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// Constructors
+
+// NewBitSet makes a new BitSet with room for the integers [0, capacity)
+// without reallocating.
+func NewBitSet(capacity int) *BitSet {
+	return &BitSet{make([]uint64, 0, (capacity+wordSize-1)/wordSize)}
+}
+
+// AsBitSet returns a BitSet containing the elements of the given slice.
+// It panics if any element is negative.
+func AsBitSet(elems []int) *BitSet {
+	b := NewBitSet(0)
+	for _, i := range elems {
+		b.Add(i)
+	}
+	return b
+}
+
+// BitSetFromSet returns a BitSet containing the elements of the given Set.
+// It returns an error if the Set contains a negative integer.
+func BitSetFromSet(s *Set[int]) (*BitSet, error) {
+	b := NewBitSet(0)
+	for i := range *s {
+		if i < 0 {
+			return nil, errNegativeBit(i)
+		}
+		b.Add(i)
+	}
+	return b, nil
+}
+
+// ToSet returns a Set containing the elements of this BitSet.
+func (b *BitSet) ToSet() *Set[int] {
+	s := NewSet[int](b.Count())
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		s.Add(i)
+	}
+	return s
+}
+
+// Slice returns a slice containing the elements of this BitSet, in
+// increasing order.
+func (b *BitSet) Slice() []int {
+	slice := make([]int, 0, b.Count())
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		slice = append(slice, i)
+	}
+	return slice
+}
+
+// Basic (non-mutating) functions
+
+// Count returns the number of elements in this BitSet.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Size returns the number of elements in this BitSet. It is equivalent to
+// Count.
+func (b *BitSet) Size() int {
+	return b.Count()
+}
+
+// IsEmpty returns true if this BitSet is empty.
+func (b *BitSet) IsEmpty() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns true if the given integer is in the BitSet.
+func (b *BitSet) Contains(i int) bool {
+	if i < 0 || i/wordSize >= len(b.words) {
+		return false
+	}
+	return b.words[i/wordSize]&(1<<(i%wordSize)) != 0
+}
+
+// NextSet returns the smallest element of this BitSet which is at least i.
+// It returns false if there is no such element.
+func (b *BitSet) NextSet(i int) (int, bool) {
+	if i < 0 {
+		i = 0
+	}
+	w := i / wordSize
+	if w >= len(b.words) {
+		return 0, false
+	}
+	// mask off bits below i
+	word := b.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + bits.TrailingZeros64(word), true
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != 0 {
+			return w*wordSize + bits.TrailingZeros64(b.words[w]), true
+		}
+	}
+	return 0, false
+}
+
+// NextClear returns the smallest non-negative integer which is at least i
+// and is not in this BitSet.
+func (b *BitSet) NextClear(i int) int {
+	if i < 0 {
+		i = 0
+	}
+	w := i / wordSize
+	if w >= len(b.words) {
+		return i
+	}
+	word := ^b.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + bits.TrailingZeros64(word)
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != ^uint64(0) {
+			return w*wordSize + bits.TrailingZeros64(^b.words[w])
+		}
+	}
+	return len(b.words) * wordSize
+}
+
+// Basic (mutating) functions
+
+// Add adds i to this BitSet, if it is not already in the BitSet.
+func (b *BitSet) Add(i int) {
+	b.grow(i)
+	b.words[i/wordSize] |= 1 << (i % wordSize)
+}
+
+// Remove removes i from this BitSet. It returns false if i was not in the
+// BitSet to begin with, and returns true if i was removed from the BitSet.
+func (b *BitSet) Remove(i int) bool {
+	ret := b.Contains(i)
+	if ret {
+		b.words[i/wordSize] &^= 1 << (i % wordSize)
+	}
+	return ret
+}
+
+// Toggle adds i to this BitSet if it is not in the BitSet, and removes it
+// otherwise. It returns true if i is in the BitSet afterwards.
+func (b *BitSet) Toggle(i int) bool {
+	b.grow(i)
+	b.words[i/wordSize] ^= 1 << (i % wordSize)
+	return b.Contains(i)
+}
+
+// AddRange adds all integers in the range [low, high) to this BitSet.
+func (b *BitSet) AddRange(low, high int) {
+	if low >= high {
+		return
+	}
+	b.grow(high - 1)
+	b.applyRange(low, high, func(w *uint64, mask uint64) { *w |= mask })
+}
+
+// RemoveRange removes all integers in the range [low, high) from this BitSet.
+func (b *BitSet) RemoveRange(low, high int) {
+	if low < 0 {
+		low = 0
+	}
+	if max := len(b.words) * wordSize; high > max {
+		high = max
+	}
+	if low >= high {
+		return
+	}
+	b.applyRange(low, high, func(w *uint64, mask uint64) { *w &^= mask })
+}
+
+// ToggleRange toggles all integers in the range [low, high) in this BitSet.
+func (b *BitSet) ToggleRange(low, high int) {
+	if low >= high {
+		return
+	}
+	b.grow(high - 1)
+	b.applyRange(low, high, func(w *uint64, mask uint64) { *w ^= mask })
+}
+
+// Clear removes all elements from this BitSet.
+func (b *BitSet) Clear() {
+	b.words = b.words[:0]
+}
+
+// Copying functions
+
+// Copy returns a copy of the given BitSet.
+func (b *BitSet) Copy() *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitSet{words}
+}
+
+// Iteration
+
+type bitSetIterator struct {
+	b    *BitSet
+	next int
+	ok   bool
+}
+
+func (i *bitSetIterator) HasNext() bool {
+	return i.ok
+}
+
+func (i *bitSetIterator) Next() int {
+	n := i.next
+	i.next, i.ok = i.b.NextSet(n + 1)
+	return n
+}
+
+// Iterate returns an Iterator iterating over the elements of this BitSet in
+// increasing order.
+func (b *BitSet) Iterate() Iterator[int] {
+	next, ok := b.NextSet(0)
+	return &bitSetIterator{b, next, ok}
+}
+
+// Set operations
+
+// Or returns the BitSet of all elements which are in this BitSet or any of
+// the given BitSets. It is the BitSet equivalent of Union.
+func (b *BitSet) Or(others ...*BitSet) *BitSet {
+	or := b.Copy()
+	for _, other := range others {
+		if len(other.words) > len(or.words) {
+			or.words = append(or.words, make([]uint64, len(other.words)-len(or.words))...)
+		}
+		for i, w := range other.words {
+			or.words[i] |= w
+		}
+	}
+	return or
+}
+
+// And returns the BitSet of elements which are in this BitSet and all of
+// the given BitSets. It is the BitSet equivalent of Intersection.
+func (b *BitSet) And(others ...*BitSet) *BitSet {
+	and := b.Copy()
+	for _, other := range others {
+		if len(other.words) < len(and.words) {
+			and.words = and.words[:len(other.words)]
+		}
+		for i := range and.words {
+			and.words[i] &= other.words[i]
+		}
+	}
+	return and
+}
+
+// AndNot returns the BitSet of all elements in this BitSet which are not in
+// other. It is the BitSet equivalent of Difference.
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	diff := b.Copy()
+	for i := 0; i < len(diff.words) && i < len(other.words); i++ {
+		diff.words[i] &^= other.words[i]
+	}
+	return diff
+}
+
+// Xor returns the BitSet of all elements which are in exactly one of this
+// BitSet and other. It is the BitSet equivalent of SymmetricDifference.
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	xor := b.Copy()
+	if len(other.words) > len(xor.words) {
+		xor.words = append(xor.words, make([]uint64, len(other.words)-len(xor.words))...)
+	}
+	for i, w := range other.words {
+		xor.words[i] ^= w
+	}
+	return xor
+}
+
+// Internal methods
+
+// grow ensures that the BitSet has room for the integer i.
+func (b *BitSet) grow(i int) {
+	if i < 0 {
+		panic(errNegativeBit(i))
+	}
+	if n := i/wordSize + 1; n > len(b.words) {
+		b.words = append(b.words, make([]uint64, n-len(b.words))...)
+	}
+}
+
+// applyRange calls f on each word overlapping the range [low, high), with a
+// mask of the bits in that word which lie in the range.
+// The range must be non-empty and within the BitSet.
+func (b *BitSet) applyRange(low, high int, f func(w *uint64, mask uint64)) {
+	if low < 0 {
+		panic(errNegativeBit(low))
+	}
+	first, last := low/wordSize, (high-1)/wordSize
+	for w := first; w <= last; w++ {
+		mask := ^uint64(0)
+		if w == first {
+			mask &= ^uint64(0) << (low % wordSize)
+		}
+		if w == last {
+			mask &= ^uint64(0) >> (wordSize - 1 - (high-1)%wordSize)
+		}
+		f(&b.words[w], mask)
+	}
+}
+
+// Errors
+
+func errNegativeBit(i int) error {
+	return fmt.Errorf("negative integer %d cannot be stored in BitSet", i)
+}
@@ -0,0 +1,175 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+const bitSetDomain = 300 // spans several words
+
+func assertBitSetMatches(t *testing.T, b *BitSet, ref *Set[int]) {
+	t.Helper()
+	want := ref.Slice()
+	slices.Sort(want)
+	if got := b.Slice(); !slices.Equal(got, want) {
+		t.Fatalf("BitSet = %v, want %v", got, want)
+	}
+	if b.Count() != ref.Size() || b.IsEmpty() != ref.IsEmpty() {
+		t.Fatalf("Count() = %d, IsEmpty() = %v; want %d", b.Count(), b.IsEmpty(), ref.Size())
+	}
+}
+
+func TestBitSetAgainstSet(t *testing.T) {
+	r := rand.New(seeded(33))
+	b := NewBitSet(0)
+	ref := NewSet[int](0)
+
+	for i := 0; i < 3000; i++ {
+		x := r.IntN(bitSetDomain)
+		lo := r.IntN(bitSetDomain)
+		hi := lo + r.IntN(130)
+		switch r.IntN(6) {
+		case 0, 1:
+			b.Add(x)
+			ref.Add(x)
+		case 2:
+			if b.Remove(x) != ref.Remove(x) {
+				t.Fatalf("Remove(%d) disagrees with Set", x)
+			}
+		case 3:
+			want := !ref.Contains(x)
+			if want {
+				ref.Add(x)
+			} else {
+				ref.Remove(x)
+			}
+			if b.Toggle(x) != want {
+				t.Fatalf("Toggle(%d) returned %v", x, !want)
+			}
+		case 4:
+			switch r.IntN(3) {
+			case 0:
+				b.AddRange(lo, hi)
+				for y := lo; y < hi; y++ {
+					ref.Add(y)
+				}
+			case 1:
+				b.RemoveRange(lo, hi)
+				for y := lo; y < hi; y++ {
+					ref.Remove(y)
+				}
+			case 2:
+				b.ToggleRange(lo, hi)
+				for y := lo; y < hi; y++ {
+					if !ref.Remove(y) {
+						ref.Add(y)
+					}
+				}
+			}
+		case 5:
+			if i%100 == 0 {
+				b.Clear()
+				ref = NewSet[int](0)
+			}
+		}
+
+		if b.Contains(x) != ref.Contains(x) {
+			t.Fatalf("Contains(%d) disagrees with Set", x)
+		}
+		assertBitSetMatches(t, b, ref)
+	}
+}
+
+func TestBitSetNext(t *testing.T) {
+	b := AsBitSet([]int{0, 1, 63, 64, 65, 127, 200})
+	for i := -1; i < 260; i++ {
+		wantSet, wantOK := -1, false
+		for j := max(i, 0); j < 260; j++ {
+			if b.Contains(j) {
+				wantSet, wantOK = j, true
+				break
+			}
+		}
+		if got, ok := b.NextSet(i); ok != wantOK || (ok && got != wantSet) {
+			t.Errorf("NextSet(%d) = %d, %v; want %d, %v", i, got, ok, wantSet, wantOK)
+		}
+
+		wantClear := max(i, 0)
+		for b.Contains(wantClear) {
+			wantClear++
+		}
+		if got := b.NextClear(i); got != wantClear {
+			t.Errorf("NextClear(%d) = %d, want %d", i, got, wantClear)
+		}
+	}
+
+	full := NewBitSet(0)
+	full.AddRange(0, 128)
+	if got := full.NextClear(0); got != 128 {
+		t.Errorf("NextClear on full words = %d, want 128", got)
+	}
+}
+
+func TestBitSetOperations(t *testing.T) {
+	r := rand.New(seeded(34))
+	random := func() (*BitSet, *Set[int]) {
+		b, s := NewBitSet(0), NewSet[int](0)
+		n := r.IntN(bitSetDomain)
+		for i := 0; i < n; i++ {
+			x := r.IntN(r.IntN(bitSetDomain) + 1)
+			b.Add(x)
+			s.Add(x)
+		}
+		return b, s
+	}
+
+	for trial := 0; trial < 200; trial++ {
+		b1, s1 := random()
+		b2, s2 := random()
+		b3, s3 := random()
+		before := b1.Copy()
+
+		assertBitSetMatches(t, b1.Or(b2, b3), Union(s1, s2, s3))
+		assertBitSetMatches(t, b1.And(b2, b3), Intersection(s1, s2, s3))
+		assertBitSetMatches(t, b1.AndNot(b2), Difference(s1, s2))
+		assertBitSetMatches(t, b1.Xor(b2), SymmetricDifference(s1, s2))
+		if !slices.Equal(b1.Slice(), before.Slice()) {
+			t.Fatalf("set operation modified its receiver")
+		}
+	}
+}
+
+func TestBitSetConversions(t *testing.T) {
+	s := AsSet([]int{5, 0, 700, 64})
+	b, err := BitSetFromSet(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.ToSet().Equal(s) {
+		t.Errorf("ToSet() = %v, want %v", b.ToSet().Slice(), s.Slice())
+	}
+	if got := b.String(); got != "{0, 5, 64, 700}" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := NewBitSet(10).String(); got != "{}" {
+		t.Errorf("empty String() = %q", got)
+	}
+	if _, err := BitSetFromSet(AsSet([]int{1, -1})); err == nil {
+		t.Errorf("expected error converting a Set with negative elements")
+	}
+
+	var it []int
+	for i := b.Iterate(); i.HasNext(); {
+		it = append(it, i.Next())
+	}
+	if !slices.Equal(it, b.Slice()) {
+		t.Errorf("Iterate() = %v, want %v", it, b.Slice())
+	}
+
+	cp := b.Copy()
+	cp.Add(1)
+	if b.Contains(1) {
+		t.Errorf("modifying a copy changed the original")
+	}
+}
@@ -0,0 +1,355 @@
+package collections
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// BloomFilter is a probabilistic set, which can report that an element is
+// definitely not in the set, or that it might be. It uses much less memory
+// than a Set, at the cost of occasional false positives.
+type BloomFilter[T any] struct {
+	bits   BitSet
+	m      uint64 // number of bits
+	k      int    // number of hash functions
+	hasher Hasher[T]
+}
+
+// Constructors
+
+// NewBloomFilter makes a new BloomFilter sized to hold expectedItems elements
+// with a false positive rate of at most fpRate. The hasher is used to hash
+// elements; if it is nil, the default Hasher for T is used (see Hasher).
+// It panics if fpRate is not strictly between 0 and 1, or if hasher is nil
+// and T has no default Hasher.
+func NewBloomFilter[T any](expectedItems int, fpRate float64, hasher Hasher[T]) *BloomFilter[T] {
+	m, k := bloomParams(expectedItems, fpRate)
+	return newBloomFilter(m, k, hasher)
+}
+
+func newBloomFilter[T any](m uint64, k int, hasher Hasher[T]) *BloomFilter[T] {
+	f := &BloomFilter[T]{m: m, k: k, hasher: hasherOrDefault(hasher)}
+	f.bits.grow(int(m - 1))
+	return f
+}
+
+// UnmarshalBloomFilter decodes a BloomFilter which was encoded using
+// MarshalBinary. The hasher must be the same as the one used by the encoded
+// BloomFilter; a nil hasher selects the same default as NewBloomFilter.
+func UnmarshalBloomFilter[T any](data []byte, hasher Hasher[T]) (*BloomFilter[T], error) {
+	r := binaryReader{data: data}
+	m, k, err := readBloomHeader(&r, bloomMagic, "BloomFilter")
+	if err != nil {
+		return nil, err
+	}
+	f := newBloomFilter(m, k, hasher)
+	for i := range f.bits.words {
+		f.bits.words[i] = r.uint64()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, errInvalidEncoding("BloomFilter", "trailing data")
+	}
+	return f, nil
+}
+
+// Basic (non-mutating) functions
+
+// MayContain returns false if t is definitely not in this BloomFilter, and
+// true if it might be.
+func (f *BloomFilter[T]) MayContain(t T) bool {
+	h1, h2 := hashPair(f.hasher(t))
+	for i := 0; i < f.k; i++ {
+		if !f.bits.Contains(bloomIndex(h1, h2, i, f.m)) {
+			return false
+		}
+	}
+	return true
+}
+
+// BitSize returns the number of bits used by this BloomFilter.
+func (f *BloomFilter[T]) BitSize() int {
+	return int(f.m)
+}
+
+// HashCount returns the number of hash functions used by this BloomFilter.
+func (f *BloomFilter[T]) HashCount() int {
+	return f.k
+}
+
+// EstimatedFalsePositiveRate estimates the current probability that
+// MayContain returns true for an element which was never added, based on
+// the proportion of bits set.
+func (f *BloomFilter[T]) EstimatedFalsePositiveRate() float64 {
+	return math.Pow(float64(f.bits.Count())/float64(f.m), float64(f.k))
+}
+
+// Basic (mutating) functions
+
+// Add adds t to this BloomFilter.
+func (f *BloomFilter[T]) Add(t T) {
+	h1, h2 := hashPair(f.hasher(t))
+	for i := 0; i < f.k; i++ {
+		f.bits.Add(bloomIndex(h1, h2, i, f.m))
+	}
+}
+
+// Clear removes all elements from this BloomFilter.
+func (f *BloomFilter[T]) Clear() {
+	clear(f.bits.words)
+}
+
+// Copying functions
+
+// Copy returns a copy of the given BloomFilter.
+func (f *BloomFilter[T]) Copy() *BloomFilter[T] {
+	return &BloomFilter[T]{*f.bits.Copy(), f.m, f.k, f.hasher}
+}
+
+// Union returns a BloomFilter which may contain every element which may be
+// in this BloomFilter or any of the given BloomFilters. All the BloomFilters
+// must have the same size, number of hash functions and hasher; Union returns
+// an error if the sizes or number of hash functions differ.
+func (f *BloomFilter[T]) Union(others ...*BloomFilter[T]) (*BloomFilter[T], error) {
+	union := f.Copy()
+	for _, other := range others {
+		if other.m != f.m || other.k != f.k {
+			return nil, errIncompatibleFilters(f.m, f.k, other.m, other.k)
+		}
+		for i, w := range other.bits.words {
+			union.bits.words[i] |= w
+		}
+	}
+	return union, nil
+}
+
+// Serialization
+
+var bloomMagic = [4]byte{'B', 'L', 'M', 1}
+
+// MarshalBinary encodes this BloomFilter in a portable binary format,
+// implementing encoding.BinaryMarshaler. The hasher is not encoded; use
+// UnmarshalBloomFilter with the same hasher to decode the result.
+//
+// The format is: the 4-byte header "BLM\x01", the number of bits as a uint64,
+// the number of hash functions as a uint32, then the bits as uint64 words.
+// All integers are little-endian.
+func (f *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	data := appendBloomHeader(nil, bloomMagic, f.m, f.k)
+	for _, w := range f.bits.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// CountingBloomFilter is a BloomFilter which also supports removing
+// elements, by keeping a small counter in place of each bit. It uses 8 times
+// as much memory as a BloomFilter with the same parameters.
+//
+// Counters saturate at 255; once a counter is saturated, it is never
+// decremented, so that Remove can never cause a false negative.
+type CountingBloomFilter[T any] struct {
+	counters []uint8
+	k        int
+	hasher   Hasher[T]
+}
+
+// Constructors
+
+// NewCountingBloomFilter makes a new CountingBloomFilter sized to hold
+// expectedItems elements with a false positive rate of at most fpRate. As in
+// NewBloomFilter, a nil hasher selects the default Hasher for T.
+// It panics if fpRate is not strictly between 0 and 1, or if hasher is nil
+// and T has no default Hasher.
+func NewCountingBloomFilter[T any](expectedItems int, fpRate float64, hasher Hasher[T]) *CountingBloomFilter[T] {
+	m, k := bloomParams(expectedItems, fpRate)
+	return &CountingBloomFilter[T]{make([]uint8, m), k, hasherOrDefault(hasher)}
+}
+
+// UnmarshalCountingBloomFilter decodes a CountingBloomFilter which was
+// encoded using MarshalBinary. The hasher must be the same as the one used by
+// the encoded CountingBloomFilter; a nil hasher selects the same default as
+// NewCountingBloomFilter.
+func UnmarshalCountingBloomFilter[T any](data []byte, hasher Hasher[T]) (*CountingBloomFilter[T], error) {
+	r := binaryReader{data: data}
+	m, k, err := readBloomHeader(&r, countingBloomMagic, "CountingBloomFilter")
+	if err != nil {
+		return nil, err
+	}
+	counters := r.next(int(m))
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, errInvalidEncoding("CountingBloomFilter", "trailing data")
+	}
+	f := &CountingBloomFilter[T]{make([]uint8, m), k, hasherOrDefault(hasher)}
+	copy(f.counters, counters)
+	return f, nil
+}
+
+// Basic (non-mutating) functions
+
+// MayContain returns false if t is definitely not in this
+// CountingBloomFilter, and true if it might be.
+func (f *CountingBloomFilter[T]) MayContain(t T) bool {
+	h1, h2 := hashPair(f.hasher(t))
+	for i := 0; i < f.k; i++ {
+		if f.counters[bloomIndex(h1, h2, i, f.size())] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BitSize returns the number of counters used by this CountingBloomFilter.
+func (f *CountingBloomFilter[T]) BitSize() int {
+	return len(f.counters)
+}
+
+// HashCount returns the number of hash functions used by this
+// CountingBloomFilter.
+func (f *CountingBloomFilter[T]) HashCount() int {
+	return f.k
+}
+
+// Basic (mutating) functions
+
+// Add adds t to this CountingBloomFilter.
+func (f *CountingBloomFilter[T]) Add(t T) {
+	h1, h2 := hashPair(f.hasher(t))
+	for i := 0; i < f.k; i++ {
+		j := bloomIndex(h1, h2, i, f.size())
+		if f.counters[j] < math.MaxUint8 {
+			f.counters[j]++
+		}
+	}
+}
+
+// Remove removes one occurrence of t from this CountingBloomFilter. It
+// returns false, without modifying the filter, if t was definitely not in the
+// CountingBloomFilter, and returns true otherwise.
+//
+// Only remove elements which were previously added: removing an element
+// which was never added (but gave a false positive) can cause false
+// negatives for other elements.
+func (f *CountingBloomFilter[T]) Remove(t T) bool {
+	if !f.MayContain(t) {
+		return false
+	}
+	h1, h2 := hashPair(f.hasher(t))
+	for i := 0; i < f.k; i++ {
+		j := bloomIndex(h1, h2, i, f.size())
+		if f.counters[j] < math.MaxUint8 {
+			f.counters[j]--
+		}
+	}
+	return true
+}
+
+// Clear removes all elements from this CountingBloomFilter.
+func (f *CountingBloomFilter[T]) Clear() {
+	clear(f.counters)
+}
+
+// Copying functions
+
+// Copy returns a copy of the given CountingBloomFilter.
+func (f *CountingBloomFilter[T]) Copy() *CountingBloomFilter[T] {
+	counters := make([]uint8, len(f.counters))
+	copy(counters, f.counters)
+	return &CountingBloomFilter[T]{counters, f.k, f.hasher}
+}
+
+// Union returns a CountingBloomFilter containing the elements of this
+// CountingBloomFilter and all of the given CountingBloomFilters, by adding
+// their counters. All the CountingBloomFilters must have the same size,
+// number of hash functions and hasher; Union returns an error if the sizes or
+// number of hash functions differ.
+func (f *CountingBloomFilter[T]) Union(others ...*CountingBloomFilter[T]) (*CountingBloomFilter[T], error) {
+	union := f.Copy()
+	for _, other := range others {
+		if other.size() != f.size() || other.k != f.k {
+			return nil, errIncompatibleFilters(f.size(), f.k, other.size(), other.k)
+		}
+		for i, c := range other.counters {
+			union.counters[i] = uint8(min(int(union.counters[i])+int(c), math.MaxUint8))
+		}
+	}
+	return union, nil
+}
+
+// Serialization
+
+var countingBloomMagic = [4]byte{'C', 'B', 'F', 1}
+
+// MarshalBinary encodes this CountingBloomFilter in a portable binary format,
+// implementing encoding.BinaryMarshaler. The hasher is not encoded; use
+// UnmarshalCountingBloomFilter with the same hasher to decode the result.
+//
+// The format is: the 4-byte header "CBF\x01", the number of counters as a
+// uint64, the number of hash functions as a uint32, then the counters as
+// bytes. All integers are little-endian.
+func (f *CountingBloomFilter[T]) MarshalBinary() ([]byte, error) {
+	data := appendBloomHeader(nil, countingBloomMagic, f.size(), f.k)
+	return append(data, f.counters...), nil
+}
+
+// Internal functions
+
+func (f *CountingBloomFilter[T]) size() uint64 {
+	return uint64(len(f.counters))
+}
+
+// bloomParams returns the optimal number of bits m and hash functions k for
+// a Bloom filter holding n elements with false positive rate p.
+func bloomParams(n int, p float64) (m uint64, k int) {
+	if !(p > 0 && p < 1) {
+		panic(fmt.Sprintf("false positive rate %v not between 0 and 1", p))
+	}
+	if n < 1 {
+		n = 1
+	}
+	bits := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	m = uint64(math.Max(bits, 1))
+	k = int(math.Max(math.Round(float64(m)/float64(n)*math.Ln2), 1))
+	return
+}
+
+// bloomIndex returns the i-th index for an element with hash pair (h1, h2)
+// in a Bloom filter of m bits.
+func bloomIndex(h1, h2 uint64, i int, m uint64) int {
+	return int((h1 + uint64(i)*h2) % m)
+}
+
+func appendBloomHeader(data []byte, magic [4]byte, m uint64, k int) []byte {
+	data = append(data, magic[:]...)
+	data = binary.LittleEndian.AppendUint64(data, m)
+	return binary.LittleEndian.AppendUint32(data, uint32(k))
+}
+
+func readBloomHeader(r *binaryReader, magic [4]byte, typ string) (m uint64, k int, err error) {
+	if h := r.next(4); h == nil || [4]byte(h) != magic {
+		return 0, 0, errInvalidEncoding(typ, "bad header")
+	}
+	m = r.uint64()
+	k = int(r.uint32())
+	if r.err != nil {
+		return 0, 0, r.err
+	}
+	// Check m against the remaining data before allocating.
+	if m == 0 || k == 0 || m > uint64(len(r.data))*8 {
+		return 0, 0, errInvalidEncoding(typ, "bad parameters")
+	}
+	return m, k, nil
+}
+
+// Errors
+
+func errIncompatibleFilters(m1 uint64, k1 int, m2 uint64, k2 int) error {
+	return fmt.Errorf("incompatible filters: %d bits and %d hashes vs %d bits and %d hashes",
+		m1, k1, m2, k2)
+}
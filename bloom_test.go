@@ -0,0 +1,246 @@
+package collections
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, p = 10000, 0.01
+	f := NewBloomFilter[int](n, p, nil)
+	for i := 0; i < n; i++ {
+		f.Add(i)
+	}
+	for i := 0; i < n; i++ {
+		if !f.MayContain(i) {
+			t.Fatalf("false negative for %d", i)
+		}
+	}
+
+	const trials = 100000
+	falsePositives := 0
+	for i := n; i < n+trials; i++ {
+		if f.MayContain(i) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / trials; rate > 1.5*p {
+		t.Errorf("false positive rate %.4f, want at most about %v", rate, p)
+	}
+	if est := f.EstimatedFalsePositiveRate(); est > 1.5*p || est < p/1.5 {
+		t.Errorf("estimated false positive rate %.4f, want about %v", est, p)
+	}
+
+	f.Clear()
+	if f.MayContain(0) {
+		t.Errorf("cleared filter may contain 0")
+	}
+}
+
+func TestBloomFilterDefaultHashers(t *testing.T) {
+	s := NewBloomFilter[string](100, 0.01, nil)
+	s.Add("hello")
+	b := NewBloomFilter[[]byte](100, 0.01, nil)
+	b.Add([]byte("hello"))
+	u := NewCountingBloomFilter[uint32](100, 0.01, nil)
+	u.Add(7)
+	fl := NewBloomFilter[float64](100, 0.01, nil)
+	fl.Add(1.5)
+	if !s.MayContain("hello") || !b.MayContain([]byte("hello")) || !u.MayContain(7) || !fl.MayContain(1.5) {
+		t.Errorf("false negative with a default hasher")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for a nil hasher on a type with no default")
+		}
+	}()
+	NewBloomFilter[struct{ x int }](100, 0.01, nil)
+}
+
+func TestDefaultHasherNamedTypes(t *testing.T) {
+	type small int8
+	type id uint16
+	type blob []byte
+	type celsius float64
+
+	if got, want := hasherOrDefault[String](nil)("hello"), HashString("hello"); got != want {
+		t.Errorf("default Hasher for String gave %x, want HashString's %x", got, want)
+	}
+	if got, want := hasherOrDefault[small](nil)(-3), HashInt[int8](-3); got != want {
+		t.Errorf("default Hasher for a named int8 gave %x, want HashInt's %x", got, want)
+	}
+	if got, want := hasherOrDefault[id](nil)(7), HashInt[uint16](7); got != want {
+		t.Errorf("default Hasher for a named uint16 gave %x, want HashInt's %x", got, want)
+	}
+	if got, want := hasherOrDefault[blob](nil)(blob("hi")), HashBytes([]byte("hi")); got != want {
+		t.Errorf("default Hasher for a named []byte gave %x, want HashBytes's %x", got, want)
+	}
+	if got, want := hasherOrDefault[celsius](nil)(1.5), HashFloat(1.5); got != want {
+		t.Errorf("default Hasher for a named float64 gave %x, want HashFloat's %x", got, want)
+	}
+
+	f := NewBloomFilter[String](100, 0.01, nil)
+	f.Add("hello")
+	if !f.MayContain("hello") {
+		t.Errorf("false negative with the default Hasher for String")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for a slice type other than []byte")
+		}
+	}()
+	hasherOrDefault[[]int](nil)
+}
+
+func TestBloomFilterUnion(t *testing.T) {
+	f1 := NewBloomFilter[string](1000, 0.01, HashString)
+	f2 := NewBloomFilter[string](1000, 0.01, HashString)
+	for i := 0; i < 500; i++ {
+		f1.Add(fmt.Sprint("a", i))
+		f2.Add(fmt.Sprint("b", i))
+	}
+
+	union, err := f1.Union(f2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 500; i++ {
+		if !union.MayContain(fmt.Sprint("a", i)) || !union.MayContain(fmt.Sprint("b", i)) {
+			t.Fatalf("union is missing element %d", i)
+		}
+	}
+	if f1.MayContain("b0") && f1.MayContain("b1") && f1.MayContain("b2") {
+		t.Errorf("Union appears to have modified its receiver")
+	}
+
+	for _, other := range []*BloomFilter[string]{
+		NewBloomFilter[string](2000, 0.01, HashString),
+		NewBloomFilter[string](1000, 0.0001, HashString),
+	} {
+		if _, err := f1.Union(other); err == nil {
+			t.Errorf("expected error for incompatible filters (%d bits, %d hashes)",
+				other.BitSize(), other.HashCount())
+		}
+	}
+
+	c1 := NewCountingBloomFilter[int](100, 0.01, nil)
+	if _, err := c1.Union(NewCountingBloomFilter[int](200, 0.01, nil)); err == nil {
+		t.Errorf("expected error for incompatible counting filters")
+	}
+}
+
+func TestCountingBloomFilterRemove(t *testing.T) {
+	f := NewCountingBloomFilter[int](1000, 0.01, nil)
+	for i := 0; i < 1000; i++ {
+		f.Add(i)
+	}
+	f.Add(0) // added twice
+
+	for i := 1; i < 500; i++ {
+		if !f.Remove(i) {
+			t.Fatalf("Remove(%d) = false for an added element", i)
+		}
+	}
+	for i := 500; i < 1000; i++ {
+		if !f.MayContain(i) {
+			t.Fatalf("false negative for %d after removing other elements", i)
+		}
+	}
+	removed := 0
+	for i := 1; i < 500; i++ {
+		if !f.MayContain(i) {
+			removed++
+		}
+	}
+	if removed < 450 {
+		t.Errorf("only %d of 499 removed elements are reported absent", removed)
+	}
+
+	if !f.Remove(0) || !f.MayContain(0) {
+		t.Errorf("element added twice should remain after one Remove")
+	}
+
+	empty := NewCountingBloomFilter[int](1000, 0.01, nil)
+	if empty.Remove(1) {
+		t.Errorf("Remove from an empty filter returned true")
+	}
+
+	// Saturated counters are never decremented.
+	sat := NewCountingBloomFilter[int](10, 0.1, nil)
+	for i := 0; i < 300; i++ {
+		sat.Add(1)
+	}
+	for i := 0; i < 300; i++ {
+		sat.Remove(1)
+	}
+	if !sat.MayContain(1) {
+		t.Errorf("saturated counters were decremented")
+	}
+}
+
+func TestBloomFilterMarshal(t *testing.T) {
+	f := NewBloomFilter[string](1000, 0.01, nil)
+	for i := 0; i < 1000; i++ {
+		f.Add(fmt.Sprint(i))
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UnmarshalBloomFilter[string](data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.BitSize() != f.BitSize() || got.HashCount() != f.HashCount() {
+		t.Errorf("decoded parameters differ")
+	}
+	for i := 0; i < 2000; i++ {
+		if s := fmt.Sprint(i); got.MayContain(s) != f.MayContain(s) {
+			t.Fatalf("decoded filter differs on %q", s)
+		}
+	}
+
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"header":    data[:3],
+		"params":    data[:10],
+		"truncated": data[:len(data)-1],
+		"trailing":  append(append([]byte{}, data...), 0),
+		"magic":     append([]byte("CBF\x01"), data[4:]...),
+	} {
+		if _, err := UnmarshalBloomFilter[string](bad, nil); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCountingBloomFilterMarshal(t *testing.T) {
+	f := NewCountingBloomFilter[int](100, 0.01, nil)
+	for i := 0; i < 100; i++ {
+		f.Add(i)
+	}
+	data, _ := f.MarshalBinary()
+	got, err := UnmarshalCountingBloomFilter[int](data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if !got.Remove(i) {
+			t.Fatalf("decoded filter lost element %d", i)
+		}
+	}
+
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"truncated": data[:len(data)-1],
+		"trailing":  append(append([]byte{}, data...), 0),
+		"magic":     append([]byte("BLM\x01"), data[4:]...),
+	} {
+		if _, err := UnmarshalCountingBloomFilter[int](bad, nil); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
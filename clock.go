@@ -0,0 +1,111 @@
+package collections
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of the current time, used by time-based collections such
+// as ExpiringMap. It can be replaced by a ManualClock to control time
+// explicitly.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel which receives the current time once the
+	// given duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is a Clock which uses the system time.
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After is equivalent to time.After.
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrDefault returns the given Clock, or a SystemClock if it is nil.
+func clockOrDefault(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock{}
+	}
+	return clock
+}
+
+// ManualClock is a Clock whose time only changes when Advance is called.
+// It is safe for concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []manualClockWaiter
+	// changed is signalled when a waiter is added.
+	changed *sync.Cond
+}
+
+type manualClockWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewManualClock makes a new ManualClock set to the given time.
+func NewManualClock(start time.Time) *ManualClock {
+	c := &ManualClock{now: start}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of this ManualClock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel which receives the time once this ManualClock has
+// been advanced by at least d.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+	} else {
+		c.waiters = append(c.waiters, manualClockWaiter{c.now.Add(d), ch})
+		c.changed.Broadcast()
+	}
+	return ch
+}
+
+// BlockUntil blocks until at least n channels returned by After are waiting
+// to fire. This lets a test wait until a goroutine is blocked on the clock,
+// before calling Advance.
+func (c *ManualClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// Advance moves this ManualClock forward by d, firing any channels returned
+// by After whose duration has now elapsed.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if c.now.Before(w.deadline) {
+			pending = append(pending, w)
+		} else {
+			w.ch <- c.now
+		}
+	}
+	clear(c.waiters[len(pending):])
+	c.waiters = pending
+}
@@ -0,0 +1,22 @@
+package collections
+
+// Cloner is implemented by types which can make a deep copy of themselves.
+// It is used by the CopyDeep methods when no clone function is given.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// cloneFunc returns clone if it is non-nil. Otherwise, it returns a function
+// which clones elements implementing Cloner[T], and returns other elements
+// unchanged.
+func cloneFunc[T any](clone func(t T) T) func(t T) T {
+	if clone != nil {
+		return clone
+	}
+	return func(t T) T {
+		if c, ok := any(t).(Cloner[T]); ok {
+			return c.Clone()
+		}
+		return t
+	}
+}
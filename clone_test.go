@@ -0,0 +1,135 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+// box is a mutable type implementing Cloner.
+type box struct{ n int }
+
+func (b *box) Clone() *box {
+	cp := *b
+	return &cp
+}
+
+func boxes(ns ...int) []*box {
+	bs := make([]*box, len(ns))
+	for i, n := range ns {
+		bs[i] = &box{n}
+	}
+	return bs
+}
+
+// checkDeepCopy checks that got holds copies of the boxes in want, with the
+// same values but different pointers.
+func checkDeepCopy(t *testing.T, name string, got, want []*box) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: copy has %d elements, want %d", name, len(got), len(want))
+	}
+	for i := range got {
+		if got[i] == want[i] {
+			t.Errorf("%s: element %d was not cloned", name, i)
+		}
+		if got[i].n != want[i].n {
+			t.Errorf("%s: element %d has value %d, want %d", name, i, got[i].n, want[i].n)
+		}
+	}
+}
+
+func TestQueueAndStackCopy(t *testing.T) {
+	q := NewQueue[int](0)
+	q.EnqueueAll(1, 2, 3)
+	qc := q.Copy()
+	if !slices.Equal(qc.AsSlice(), []int{1, 2, 3}) {
+		t.Fatalf("Queue.Copy() = %v, want [1 2 3]", qc.AsSlice())
+	}
+	q.Dequeue()
+	q.Enqueue(4)
+	if !slices.Equal(qc.AsSlice(), []int{1, 2, 3}) {
+		t.Errorf("modifying the Queue changed its copy: %v", qc.AsSlice())
+	}
+
+	s := NewStack[int](0)
+	s.PushAll(1, 2, 3)
+	sc := s.Copy()
+	if !slices.Equal(sc.AsSlice(), []int{1, 2, 3}) {
+		t.Fatalf("Stack.Copy() = %v, want [1 2 3]", sc.AsSlice())
+	}
+	s.Pop()
+	s.Push(4)
+	if !slices.Equal(sc.AsSlice(), []int{1, 2, 3}) {
+		t.Errorf("modifying the Stack changed its copy: %v", sc.AsSlice())
+	}
+}
+
+func TestCopyDeepUsesCloner(t *testing.T) {
+	orig := boxes(1, 2, 3)
+
+	l := AsList(slices.Clone(orig))
+	checkDeepCopy(t, "List", *l.CopyDeep(nil), orig)
+
+	q := AsQueue(slices.Clone(orig))
+	checkDeepCopy(t, "Queue", q.CopyDeep(nil).AsSlice(), orig)
+
+	s := AsStack(slices.Clone(orig))
+	checkDeepCopy(t, "Stack", s.CopyDeep(nil).AsSlice(), orig)
+
+	m := NewMap[int, *box](0)
+	for _, b := range orig {
+		m.Set(b.n, b)
+	}
+	mc := m.CopyDeep(nil)
+	for k, b := range *m {
+		c, err := mc.Get(k)
+		if err != nil || c == b || c.n != b.n {
+			t.Errorf("Map: value for key %d was not cloned", k)
+		}
+	}
+
+	set := AsSet(orig)
+	setc := set.CopyDeep(nil)
+	if setc.Size() != set.Size() {
+		t.Fatalf("Set: copy has %d elements, want %d", setc.Size(), set.Size())
+	}
+	var ns []int
+	for b := range *setc {
+		if set.Contains(b) {
+			t.Errorf("Set: element %d was not cloned", b.n)
+		}
+		ns = append(ns, b.n)
+	}
+	slices.Sort(ns)
+	if !slices.Equal(ns, []int{1, 2, 3}) {
+		t.Errorf("Set: copied values %v", ns)
+	}
+
+	// Changing a clone does not affect the original.
+	(*l.CopyDeep(nil))[0].n = 100
+	if orig[0].n != 1 {
+		t.Errorf("modifying a deep copy changed the original")
+	}
+}
+
+func TestCopyDeepWithCloneFunc(t *testing.T) {
+	l := AsList([][]int{{1, 2}, {3}})
+	cp := l.CopyDeep(slices.Clone)
+	cp.At(0)[0] = 100
+	if l.At(0)[0] != 1 {
+		t.Errorf("CopyDeep with a clone function shared element storage")
+	}
+
+	// Elements not implementing Cloner are copied as-is.
+	shared := AsList([][]int{{1}})
+	if shallow := shared.CopyDeep(nil); &shallow.At(0)[0] != &shared.At(0)[0] {
+		t.Errorf("CopyDeep(nil) cloned an element which is not a Cloner")
+	}
+
+	// An explicit clone function takes priority over Cloner.
+	q := AsQueue(boxes(1))
+	qc := q.CopyDeep(func(b *box) *box { return &box{b.n * 10} })
+	if v, _ := qc.Peek(); v.n != 10 {
+		t.Errorf("clone function was not used: got %d", v.n)
+	}
+}
@@ -0,0 +1,125 @@
+package collections
+
+import (
+	"fmt"
+	"math"
+)
+
+// CountMinSketch estimates how many times each element has been added to it,
+// using a fixed amount of memory regardless of how many distinct elements are
+// added. Estimates are never too low, and are too high by at most
+// epsilon*Total() with probability at least 1-delta, where epsilon and delta
+// are the parameters given to NewCountMinSketch.
+// CountMinSketches with the same parameters and hasher can be merged.
+type CountMinSketch[T any] struct {
+	// counts[i*width+j] is column j of row i.
+	counts []uint64
+	width  int
+	depth  int
+	total  uint64
+	hasher Hasher[T]
+}
+
+// Constructors
+
+// NewCountMinSketch makes a new CountMinSketch whose estimates exceed the true
+// count by at most epsilon*Total(), with probability at least 1-delta.
+// The memory used is about 8*e/epsilon*ln(1/delta) bytes. The hasher is used
+// to hash elements; if it is nil, the default Hasher for T is used (see
+// Hasher).
+// It panics if epsilon or delta are not strictly between 0 and 1, or if
+// hasher is nil and T has no default Hasher.
+func NewCountMinSketch[T any](epsilon, delta float64, hasher Hasher[T]) *CountMinSketch[T] {
+	if !(epsilon > 0 && epsilon < 1) {
+		panic(fmt.Sprintf("epsilon %v not between 0 and 1", epsilon))
+	}
+	if !(delta > 0 && delta < 1) {
+		panic(fmt.Sprintf("delta %v not between 0 and 1", delta))
+	}
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return &CountMinSketch[T]{
+		counts: make([]uint64, width*depth),
+		width:  width,
+		depth:  depth,
+		hasher: hasherOrDefault(hasher),
+	}
+}
+
+// Basic (non-mutating) functions
+
+// Count returns an estimate of the number of times t has been added to this
+// CountMinSketch.
+func (s *CountMinSketch[T]) Count(t T) uint64 {
+	h1, h2 := hashPair(s.hasher(t))
+	count := uint64(math.MaxUint64)
+	for i := 0; i < s.depth; i++ {
+		count = min(count, s.counts[s.index(h1, h2, i)])
+	}
+	return count
+}
+
+// Total returns the total number of times elements have been added to this
+// CountMinSketch.
+func (s *CountMinSketch[T]) Total() uint64 {
+	return s.total
+}
+
+// Width returns the number of counters in each row of this CountMinSketch.
+func (s *CountMinSketch[T]) Width() int {
+	return s.width
+}
+
+// Depth returns the number of rows (hash functions) in this CountMinSketch.
+func (s *CountMinSketch[T]) Depth() int {
+	return s.depth
+}
+
+// Basic (mutating) functions
+
+// Add records that t has occurred count more times.
+func (s *CountMinSketch[T]) Add(t T, count uint64) {
+	h1, h2 := hashPair(s.hasher(t))
+	for i := 0; i < s.depth; i++ {
+		s.counts[s.index(h1, h2, i)] += count
+	}
+	s.total += count
+}
+
+// Merge adds the counts of other to this CountMinSketch. Both sketches must
+// use the same hasher; Merge returns an error if they have different
+// dimensions.
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if other.width != s.width || other.depth != s.depth {
+		return fmt.Errorf("incompatible CountMinSketches: %dx%d vs %dx%d",
+			s.depth, s.width, other.depth, other.width)
+	}
+	for i, c := range other.counts {
+		s.counts[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Clear resets all counts in this CountMinSketch to zero.
+func (s *CountMinSketch[T]) Clear() {
+	clear(s.counts)
+	s.total = 0
+}
+
+// Copying functions
+
+// Copy returns a copy of the given CountMinSketch.
+func (s *CountMinSketch[T]) Copy() *CountMinSketch[T] {
+	counts := make([]uint64, len(s.counts))
+	copy(counts, s.counts)
+	return &CountMinSketch[T]{counts, s.width, s.depth, s.total, s.hasher}
+}
+
+// Internal methods
+
+// index returns the index in counts of the counter for the element with hash
+// pair (h1, h2) in row i.
+func (s *CountMinSketch[T]) index(h1, h2 uint64, i int) int {
+	return i*s.width + bloomIndex(h1, h2, i, uint64(s.width))
+}
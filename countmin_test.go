@@ -0,0 +1,106 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// skewedStream adds a seeded, heavily skewed stream of keys to s, returning
+// the true counts.
+func skewedStream(s *CountMinSketch[int], seed uint64, adds int) map[int]uint64 {
+	r := rand.New(seeded(seed))
+	zipf := rand.NewZipf(r, 1.2, 1, 20000)
+	counts := map[int]uint64{}
+	for i := 0; i < adds; i++ {
+		k := int(zipf.Uint64())
+		n := uint64(r.IntN(3) + 1)
+		s.Add(k, n)
+		counts[k] += n
+	}
+	return counts
+}
+
+func TestCountMinSketchErrorBounds(t *testing.T) {
+	const epsilon, delta = 0.001, 0.01
+	for seed := uint64(0); seed < 5; seed++ {
+		s := NewCountMinSketch[int](epsilon, delta, nil)
+		counts := skewedStream(s, seed, 200000)
+
+		total := uint64(0)
+		for _, c := range counts {
+			total += c
+		}
+		if s.Total() != total {
+			t.Fatalf("Total() = %d, want %d", s.Total(), total)
+		}
+
+		// Check every key seen, and as many unseen keys (true count 0).
+		bound := epsilon * float64(s.Total())
+		exceeded, checked := 0, 0
+		check := func(k int, want uint64) {
+			got := s.Count(k)
+			if got < want {
+				t.Fatalf("seed %d: Count(%d) = %d, below true count %d", seed, k, got, want)
+			}
+			if float64(got-want) > bound {
+				exceeded++
+			}
+			checked++
+		}
+		for k, c := range counts {
+			check(k, c)
+			check(-k-1, 0)
+		}
+		if rate := float64(exceeded) / float64(checked); rate >= delta {
+			t.Errorf("seed %d: %.4f of estimates exceed the error bound, want fewer than %v",
+				seed, rate, delta)
+		}
+	}
+}
+
+func TestCountMinSketchMerge(t *testing.T) {
+	s1 := NewCountMinSketch[int](0.01, 0.01, nil)
+	s2 := NewCountMinSketch[int](0.01, 0.01, nil)
+	c1 := skewedStream(s1, 1, 10000)
+	c2 := skewedStream(s2, 2, 10000)
+
+	combined := NewCountMinSketch[int](0.01, 0.01, nil)
+	for _, counts := range []map[int]uint64{c1, c2} {
+		for k, c := range counts {
+			combined.Add(k, c)
+		}
+	}
+
+	merged := s1.Copy()
+	if err := merged.Merge(s2); err != nil {
+		t.Fatal(err)
+	}
+	if merged.Total() != combined.Total() {
+		t.Errorf("merged Total() = %d, want %d", merged.Total(), combined.Total())
+	}
+	for k := range c1 {
+		if merged.Count(k) != combined.Count(k) {
+			t.Errorf("merged Count(%d) = %d, want %d", k, merged.Count(k), combined.Count(k))
+		}
+	}
+	for k := range c2 {
+		if merged.Count(k) != combined.Count(k) {
+			t.Errorf("merged Count(%d) = %d, want %d", k, merged.Count(k), combined.Count(k))
+		}
+	}
+	if s1.Total() == merged.Total() {
+		t.Errorf("Merge appears to have modified the original via Copy")
+	}
+
+	if err := s1.Merge(NewCountMinSketch[int](0.1, 0.01, nil)); err == nil {
+		t.Errorf("expected error merging sketches of different width")
+	}
+	if err := s1.Merge(NewCountMinSketch[int](0.01, 0.1, nil)); err == nil {
+		t.Errorf("expected error merging sketches of different depth")
+	}
+
+	s1.Clear()
+	if s1.Total() != 0 || s1.Count(1) != 0 {
+		t.Errorf("sketch not empty after Clear")
+	}
+}
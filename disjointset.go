@@ -0,0 +1,176 @@
+package collections
+
+import "fmt"
+
+// DisjointSet is an implementation of a disjoint-set (union-find) structure,
+// which partitions a collection of elements into disjoint groups. It supports
+// merging groups and checking whether two elements are in the same group in
+// nearly constant time, using path compression and union by rank.
+//
+// Elements are remembered in insertion order, so that Elements, Iterate and
+// OrderedGroups give deterministic results.
+type DisjointSet[T comparable] struct {
+	// parent maps each element to its parent; the representative of each
+	// group is its own parent.
+	parent *Map[T, T]
+	// rank is an upper bound on the height of each representative's tree.
+	rank   *Map[T, int]
+	order  *List[T]
+	groups int
+}
+
+// Constructors
+
+// NewDisjointSet makes a new empty DisjointSet with the specified initial
+// capacity.
+func NewDisjointSet[T comparable](capacity int) *DisjointSet[T] {
+	return &DisjointSet[T]{
+		parent: NewMap[T, T](capacity),
+		rank:   NewMap[T, int](capacity),
+		order:  NewList[T](capacity),
+	}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of elements in this DisjointSet.
+func (d *DisjointSet[T]) Size() int {
+	return d.order.Size()
+}
+
+// IsEmpty returns true if this DisjointSet is empty.
+func (d *DisjointSet[T]) IsEmpty() bool {
+	return d.Size() == 0
+}
+
+// Count returns the number of groups in this DisjointSet.
+func (d *DisjointSet[T]) Count() int {
+	return d.groups
+}
+
+// Contains returns true if the given element is in the DisjointSet.
+func (d *DisjointSet[T]) Contains(t T) bool {
+	return d.parent.Contains(t)
+}
+
+// Connected returns true if a and b are both in this DisjointSet, and in the
+// same group.
+func (d *DisjointSet[T]) Connected(a, b T) bool {
+	ra, errA := d.Find(a)
+	rb, errB := d.Find(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// Elements returns all elements of this DisjointSet, in insertion order.
+func (d *DisjointSet[T]) Elements() *List[T] {
+	return d.order.Copy()
+}
+
+// Groups returns a Map from the representative of each group to the Set of
+// elements in that group.
+func (d *DisjointSet[T]) Groups() *Map[T, *Set[T]] {
+	groups := NewMap[T, *Set[T]](d.groups)
+	for _, t := range *d.order {
+		r := d.find(t)
+		groups.GetOrSet(r, func() *Set[T] { return NewSet[T](0) }).Add(t)
+	}
+	return groups
+}
+
+// OrderedGroups returns the groups of this DisjointSet as Lists. The groups
+// are ordered by their earliest-inserted element, and the elements of each
+// group are in insertion order.
+func (d *DisjointSet[T]) OrderedGroups() *List[*List[T]] {
+	groups := NewLinkedMap[T, *List[T]](d.groups)
+	for _, t := range *d.order {
+		r := d.find(t)
+		g, err := groups.Get(r)
+		if err != nil {
+			g = NewList[T](0)
+			groups.Set(r, g)
+		}
+		g.Append(t)
+	}
+	return groups.Values()
+}
+
+// Basic (mutating) functions
+
+// Add adds t to this DisjointSet in a new group of its own, if it is not
+// already present. It returns true if t was added.
+func (d *DisjointSet[T]) Add(t T) bool {
+	if d.Contains(t) {
+		return false
+	}
+	d.parent.Set(t, t)
+	d.rank.Set(t, 0)
+	d.order.Append(t)
+	d.groups++
+	return true
+}
+
+// Find returns the representative of the group containing t. Two elements
+// are in the same group if and only if they have the same representative.
+// It returns an error if t is not in this DisjointSet.
+func (d *DisjointSet[T]) Find(t T) (r T, err error) {
+	if !d.Contains(t) {
+		err = errElementNotInDisjointSet(t)
+		return
+	}
+	return d.find(t), nil
+}
+
+// Union merges the groups containing a and b, adding a and b to this
+// DisjointSet first if necessary. It returns false if a and b were already in
+// the same group, and returns true if their groups were merged.
+func (d *DisjointSet[T]) Union(a, b T) bool {
+	d.Add(a)
+	d.Add(b)
+	ra, rb := d.find(a), d.find(b)
+	if ra == rb {
+		return false
+	}
+
+	// attach the shorter tree below the taller one
+	rankA, rankB := (*d.rank)[ra], (*d.rank)[rb]
+	if rankA < rankB {
+		ra, rb = rb, ra
+	}
+	d.parent.Set(rb, ra)
+	if rankA == rankB {
+		d.rank.Set(ra, rankA+1)
+	}
+	d.groups--
+	return true
+}
+
+// Iteration
+
+// Iterate returns an Iterator over the elements of this DisjointSet, in
+// insertion order.
+func (d *DisjointSet[T]) Iterate() Iterator[T] {
+	return d.Elements().Iterate()
+}
+
+// Internal methods
+
+// find returns the representative of t, which must be in the DisjointSet,
+// compressing the path from t to the representative.
+func (d *DisjointSet[T]) find(t T) T {
+	r := t
+	for p := (*d.parent)[r]; p != r; p = (*d.parent)[r] {
+		r = p
+	}
+	for t != r {
+		next := (*d.parent)[t]
+		d.parent.Set(t, r)
+		t = next
+	}
+	return r
+}
+
+// Errors
+
+func errElementNotInDisjointSet(t any) error {
+	return fmt.Errorf("element not found in DisjointSet: %v", t)
+}
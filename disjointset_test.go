@@ -0,0 +1,170 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestDisjointSetAgainstBruteForce(t *testing.T) {
+	const n = 60
+	r := rand.New(seeded(41))
+	d := NewDisjointSet[int](0)
+	// label[x] is the group of x, or -1 if x has not been added.
+	label := make([]int, n)
+	for i := range label {
+		label[i] = -1
+	}
+	groups := 0
+
+	for i := 0; i < 2000; i++ {
+		a, b := r.IntN(n), r.IntN(n)
+		switch r.IntN(4) {
+		case 0:
+			want := label[a] < 0
+			if want {
+				label[a] = a
+				groups++
+			}
+			if got := d.Add(a); got != want {
+				t.Fatalf("Add(%d) = %v, want %v", a, got, want)
+			}
+		default:
+			for _, x := range []int{a, b} {
+				if label[x] < 0 {
+					label[x] = x
+					groups++
+				}
+			}
+			want := label[a] != label[b]
+			if want {
+				old := label[b]
+				for x := range label {
+					if label[x] == old {
+						label[x] = label[a]
+					}
+				}
+				groups--
+			}
+			if got := d.Union(a, b); got != want {
+				t.Fatalf("Union(%d, %d) = %v, want %v", a, b, got, want)
+			}
+		}
+
+		if d.Count() != groups {
+			t.Fatalf("Count() = %d, want %d", d.Count(), groups)
+		}
+		a, b = r.IntN(n), r.IntN(n)
+		want := label[a] >= 0 && label[a] == label[b]
+		if got := d.Connected(a, b); got != want {
+			t.Fatalf("Connected(%d, %d) = %v, want %v", a, b, got, want)
+		}
+		if _, err := d.Find(a); (err == nil) != (label[a] >= 0) {
+			t.Fatalf("Find(%d) error = %v, but added = %v", a, err, label[a] >= 0)
+		}
+		if i%100 == 0 {
+			checkDisjointSetRanks(t, d)
+		}
+	}
+	checkDisjointSetRanks(t, d)
+
+	// Groups agrees with the brute-force labels.
+	for rep, group := range *d.Groups() {
+		for x := range *group {
+			if found, _ := d.Find(x); found != rep {
+				t.Fatalf("element %d is in the group of %d, but Find gives %d", x, rep, found)
+			}
+			for y := range label {
+				if (label[y] == label[x]) != group.Contains(y) {
+					t.Fatalf("group of %d disagrees about element %d", rep, y)
+				}
+			}
+		}
+	}
+}
+
+// checkDisjointSetRanks checks that rank increases strictly towards the
+// root, and that a tree of rank k has at least 2^k elements, which bound
+// the height of every tree by log2(Size()).
+func checkDisjointSetRanks[T comparable](t *testing.T, d *DisjointSet[T]) {
+	t.Helper()
+	treeSize := map[T]int{}
+	for _, x := range *d.order {
+		p := (*d.parent)[x]
+		if p != x && (*d.rank)[x] >= (*d.rank)[p] {
+			t.Fatalf("rank of %v is not less than the rank of its parent %v", x, p)
+		}
+		treeSize[d.find(x)]++
+	}
+	for root, size := range treeSize {
+		if rank := (*d.rank)[root]; 1<<rank > size {
+			t.Fatalf("root %v has rank %d but only %d elements", root, rank, size)
+		}
+	}
+}
+
+func TestDisjointSetOrdering(t *testing.T) {
+	d := NewDisjointSet[string](0)
+	for _, s := range []string{"e", "d", "c", "b", "a"} {
+		d.Add(s)
+	}
+	d.Union("a", "e")
+	d.Union("b", "d")
+	d.Union("d", "a")
+
+	if got := *d.Elements(); !slices.Equal(got, []string{"e", "d", "c", "b", "a"}) {
+		t.Errorf("Elements() = %v", got)
+	}
+	var it []string
+	for i := d.Iterate(); i.HasNext(); {
+		it = append(it, i.Next())
+	}
+	if !slices.Equal(it, *d.Elements()) {
+		t.Errorf("Iterate() = %v", it)
+	}
+
+	var groups [][]string
+	for _, g := range *d.OrderedGroups() {
+		groups = append(groups, *g)
+	}
+	want := [][]string{{"e", "d", "b", "a"}, {"c"}}
+	if !slices.EqualFunc(groups, want, slices.Equal) {
+		t.Errorf("OrderedGroups() = %v, want %v", groups, want)
+	}
+	if d.Groups().Size() != 2 || d.Count() != 2 {
+		t.Errorf("Groups() has %d groups, Count() = %d; want 2", d.Groups().Size(), d.Count())
+	}
+
+	if d.Add("a") || d.Union("e", "b") {
+		t.Errorf("Add or Union reported a change for existing elements")
+	}
+	if _, err := d.Find("z"); err == nil || d.Connected("z", "z") {
+		t.Errorf("missing elements should not be found or connected")
+	}
+	if d.IsEmpty() || d.Size() != 5 {
+		t.Errorf("Size() = %d, want 5", d.Size())
+	}
+}
+
+func TestDisjointSetDeepChain(t *testing.T) {
+	// Union by rank keeps trees shallow even when unions always attach to
+	// the newest element; path compression then flattens them.
+	d := NewDisjointSet[int](0)
+	const n = 1 << 12
+	for i := 1; i < n; i++ {
+		d.Union(i, i-1)
+	}
+	checkDisjointSetRanks(t, d)
+	root, _ := d.Find(0)
+	for i := 0; i < n; i++ {
+		if r, _ := d.Find(i); r != root {
+			t.Fatalf("Find(%d) = %d, want %d", i, r, root)
+		}
+		if p := (*d.parent)[i]; p != root {
+			t.Fatalf("parent of %d is %d after Find; path not compressed", i, p)
+		}
+	}
+	if d.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", d.Count())
+	}
+}
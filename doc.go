@@ -9,6 +9,12 @@ Instantiate a collection X using the collections.NewX functions, which take an i
 	q := collections.NewQueue[int](10)
 	k := collections.NewStack[byte](5)
 
+Use collections.NewLinkedMap and collections.NewLinkedSet for a map or set
+which iterates in a deterministic (insertion) order:
+
+	lm := collections.NewLinkedMap[string, int](10)
+	ls := collections.NewLinkedSet[string](10)
+
 Or convert your existing slices/maps to collections using the collections.AsX functions:
 
 	l := collections.AsList([]int{0, 1})
@@ -0,0 +1,323 @@
+package collections
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ExpiringMap is a map whose entries are removed once their time-to-live
+// (TTL) has elapsed. Expired entries are never returned; they are removed
+// lazily when accessed, by Sweep, or by a background sweeper started with
+// StartSweeping. An ExpiringMap is safe for concurrent use.
+type ExpiringMap[K comparable, V any] struct {
+	mu       sync.Mutex
+	clock    Clock
+	ttl      time.Duration
+	entries  map[K]expiringEntry[V]
+	deadline *priorityQueue[expiringDeadline[K]]
+	onExpire func(k K, v V)
+	stop     chan struct{}
+}
+
+type expiringEntry[V any] struct {
+	value   V
+	expires time.Time // zero if the entry never expires
+}
+
+// expiringDeadline records when a key is due to expire. Deadlines are not
+// removed when a key is updated or removed, so a deadline is only acted on
+// if it still matches the key's entry. Such stale deadlines are discarded
+// once they outnumber the entries, so the heap stays proportional to the
+// size of the map.
+type expiringDeadline[K any] struct {
+	key     K
+	expires time.Time
+}
+
+// Constructors
+
+// NewExpiringMap makes a new ExpiringMap whose entries expire after the
+// given default TTL. A non-positive ttl means entries do not expire unless
+// added with SetWithTTL. If clock is nil, the system clock is used.
+func NewExpiringMap[K comparable, V any](ttl time.Duration, clock Clock) *ExpiringMap[K, V] {
+	return &ExpiringMap[K, V]{
+		clock:   clockOrDefault(clock),
+		ttl:     ttl,
+		entries: make(map[K]expiringEntry[V]),
+		deadline: newPriorityQueue(func(s, t expiringDeadline[K]) bool {
+			return s.expires.Before(t.expires)
+		}),
+	}
+}
+
+// OnExpire sets a callback which is called with each entry that expires.
+// It is not called for unexpired entries removed by Remove, or for entries
+// removed by Clear. The callback is called without holding the ExpiringMap's
+// lock, so it may access the map.
+func (m *ExpiringMap[K, V]) OnExpire(f func(k K, v V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onExpire = f
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of unexpired entries in this ExpiringMap.
+func (m *ExpiringMap[K, V]) Size() int {
+	m.Sweep()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// IsEmpty returns true if this ExpiringMap has no unexpired entries.
+func (m *ExpiringMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Contains returns true if this ExpiringMap contains an unexpired entry for
+// the given key.
+func (m *ExpiringMap[K, V]) Contains(k K) bool {
+	_, err := m.Get(k)
+	return err == nil
+}
+
+// Get returns the value for the given key. It returns an error if the key is
+// not in the map or has expired.
+func (m *ExpiringMap[K, V]) Get(k K) (v V, err error) {
+	var expired []Entry[K, V]
+	defer func() { m.notify(expired) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[k]
+	if ok && m.isExpired(e, m.clock.Now()) {
+		delete(m.entries, k)
+		expired = append(expired, Entry[K, V]{k, e.value})
+		ok = false
+	}
+	if !ok {
+		err = errKeyNotFound(k)
+		return
+	}
+	return e.value, nil
+}
+
+// TTL returns the time remaining before the given key expires, or a
+// non-positive duration if the key never expires. It returns an error if the
+// key is not in the map or has expired.
+func (m *ExpiringMap[K, V]) TTL(k K) (time.Duration, error) {
+	if _, err := m.Get(k); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[k]
+	if !ok {
+		return 0, errKeyNotFound(k)
+	}
+	if e.expires.IsZero() {
+		return 0, nil
+	}
+	return e.expires.Sub(m.clock.Now()), nil
+}
+
+// Keys returns a List of the unexpired keys in this ExpiringMap, in no
+// particular order.
+func (m *ExpiringMap[K, V]) Keys() *List[K] {
+	m.Sweep()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := NewList[K](len(m.entries))
+	for k := range m.entries {
+		keys.Append(k)
+	}
+	return keys
+}
+
+// Entries returns a List of the unexpired entries in this ExpiringMap, in no
+// particular order.
+func (m *ExpiringMap[K, V]) Entries() *List[Entry[K, V]] {
+	m.Sweep()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := NewList[Entry[K, V]](len(m.entries))
+	for k, e := range m.entries {
+		entries.Append(Entry[K, V]{k, e.value})
+	}
+	return entries
+}
+
+// Basic (mutating) functions
+
+// Set sets the value for the given key, which will expire after the
+// default TTL of this ExpiringMap.
+func (m *ExpiringMap[K, V]) Set(k K, v V) {
+	m.SetWithTTL(k, v, m.ttl)
+}
+
+// SetWithTTL sets the value for the given key, which will expire after the
+// given TTL. A non-positive ttl means the entry does not expire. If it
+// replaces an entry which has expired but not yet been removed, the old entry
+// is passed to the OnExpire callback.
+func (m *ExpiringMap[K, V]) SetWithTTL(k K, v V, ttl time.Duration) {
+	var expired []Entry[K, V]
+	defer func() { m.notify(expired) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	if old, ok := m.entries[k]; ok && m.isExpired(old, now) {
+		expired = append(expired, Entry[K, V]{k, old.value})
+	}
+	e := expiringEntry[V]{value: v}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+		m.deadline.Push(expiringDeadline[K]{k, e.expires})
+	}
+	m.entries[k] = e
+	m.compact()
+}
+
+// Remove removes the given key from this ExpiringMap. It returns false if
+// the key was not in the map or had already expired, and returns true if an
+// unexpired entry was removed. An expired entry is still removed, and passed
+// to the OnExpire callback.
+func (m *ExpiringMap[K, V]) Remove(k K) bool {
+	var expired []Entry[K, V]
+	defer func() { m.notify(expired) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[k]
+	if !ok {
+		return false
+	}
+	delete(m.entries, k)
+	m.compact()
+	if m.isExpired(e, m.clock.Now()) {
+		expired = append(expired, Entry[K, V]{k, e.value})
+		return false
+	}
+	return true
+}
+
+// Clear removes all entries from this ExpiringMap.
+func (m *ExpiringMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.entries)
+	m.deadline.elems = nil
+}
+
+// Sweep removes all expired entries from this ExpiringMap, calling the
+// OnExpire callback for each one. It returns the number of entries removed.
+func (m *ExpiringMap[K, V]) Sweep() int {
+	var expired []Entry[K, V]
+	defer func() { m.notify(expired) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	for !m.deadline.IsEmpty() && !now.Before(m.deadline.elems[0].expires) {
+		d := m.deadline.Pop()
+		e, ok := m.entries[d.key]
+		if ok && e.expires.Equal(d.expires) {
+			delete(m.entries, d.key)
+			expired = append(expired, Entry[K, V]{d.key, e.value})
+		}
+	}
+	return len(expired)
+}
+
+// Background sweeping
+
+// StartSweeping starts a goroutine which calls Sweep every interval, using
+// this ExpiringMap's clock. Any sweeper already running is stopped first.
+// It panics if interval is not positive.
+//
+// The first interval starts before StartSweeping returns, and each later one
+// starts once the previous sweep (including its OnExpire callbacks) has
+// finished. So with a ManualClock, after Advance fires a sweep,
+// ManualClock.BlockUntil can be used to wait for the sweep to finish.
+func (m *ExpiringMap[K, V]) StartSweeping(interval time.Duration) {
+	if interval <= 0 {
+		panic(fmt.Sprintf("non-positive sweep interval %v", interval))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopSweepingLocked()
+	stop := make(chan struct{})
+	m.stop = stop
+
+	next := m.clock.After(interval)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-next:
+			}
+			m.Sweep()
+			select {
+			case <-stop:
+				return
+			default:
+				next = m.clock.After(interval)
+			}
+		}
+	}()
+}
+
+// StopSweeping stops the background sweeper, if one is running.
+func (m *ExpiringMap[K, V]) StopSweeping() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopSweepingLocked()
+}
+
+// Internal methods
+
+// stopSweepingLocked stops the background sweeper, if one is running. It
+// must be called while holding the lock, so that StartSweeping can replace
+// the sweeper without another call starting one in between.
+func (m *ExpiringMap[K, V]) stopSweepingLocked() {
+	if m.stop != nil {
+		close(m.stop)
+		m.stop = nil
+	}
+}
+
+// compact discards stale deadlines once they outnumber the entries. It must
+// be called while holding the lock.
+func (m *ExpiringMap[K, V]) compact() {
+	if m.deadline.Size() <= 2*len(m.entries) {
+		return
+	}
+	m.deadline.RemoveIf(func(d expiringDeadline[K]) bool {
+		e, ok := m.entries[d.key]
+		return !ok || !e.expires.Equal(d.expires)
+	})
+}
+
+func (m *ExpiringMap[K, V]) isExpired(e expiringEntry[V], now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// notify calls the OnExpire callback for the given entries. It must be
+// called without holding the lock.
+func (m *ExpiringMap[K, V]) notify(expired []Entry[K, V]) {
+	if len(expired) == 0 {
+		return
+	}
+	m.mu.Lock()
+	onExpire := m.onExpire
+	m.mu.Unlock()
+	if onExpire == nil {
+		return
+	}
+	for _, e := range expired {
+		onExpire(e.Key, e.Value)
+	}
+}
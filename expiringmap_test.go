@@ -0,0 +1,286 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+var clockStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// expiryRecorder records the keys passed to an OnExpire callback.
+type expiryRecorder struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *expiryRecorder) record(k string, _ int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, k)
+}
+
+// take returns the recorded keys in sorted order, and resets the recorder.
+func (r *expiryRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := r.keys
+	r.keys = nil
+	slices.Sort(keys)
+	return keys
+}
+
+func TestExpiringMapLazyExpiry(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	m := NewExpiringMap[string, int](time.Minute, clock)
+	var rec expiryRecorder
+	m.OnExpire(rec.record)
+
+	m.Set("a", 1)
+	m.SetWithTTL("b", 2, 2*time.Minute)
+	m.SetWithTTL("forever", 3, 0)
+
+	if ttl, err := m.TTL("a"); err != nil || ttl != time.Minute {
+		t.Errorf("TTL(a) = %v, %v", ttl, err)
+	}
+	if ttl, err := m.TTL("forever"); err != nil || ttl > 0 {
+		t.Errorf("TTL(forever) = %v, %v; want non-positive", ttl, err)
+	}
+
+	clock.Advance(time.Minute - time.Nanosecond)
+	if v, err := m.Get("a"); err != nil || v != 1 {
+		t.Errorf("Get(a) before expiry = %d, %v", v, err)
+	}
+
+	clock.Advance(time.Nanosecond)
+	if _, err := m.Get("a"); err == nil {
+		t.Errorf("Get(a) returned an expired entry")
+	}
+	if got := rec.take(); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("expired on Get: %v, want [a]", got)
+	}
+	if !m.Contains("b") || m.Size() != 2 {
+		t.Errorf("Contains(b) = %v, Size() = %d; want true, 2", m.Contains("b"), m.Size())
+	}
+
+	// Resetting a key's value restarts its TTL.
+	clock.Advance(time.Minute / 2)
+	m.SetWithTTL("b", 20, 2*time.Minute)
+	clock.Advance(time.Minute)
+	if v, err := m.Get("b"); err != nil || v != 20 {
+		t.Errorf("Get(b) after reset = %d, %v", v, err)
+	}
+
+	clock.Advance(time.Hour)
+	keys := *m.Keys()
+	if !slices.Equal(keys, []string{"forever"}) {
+		t.Errorf("Keys() = %v, want [forever]", keys)
+	}
+	if got := rec.take(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("expired: %v, want [b]", got)
+	}
+
+	// Overwriting an expired entry which has not been removed yet reports it.
+	m.Set("c", 4)
+	clock.Advance(time.Minute)
+	m.Set("c", 5)
+	if got := rec.take(); !slices.Equal(got, []string{"c"}) {
+		t.Errorf("expired on overwrite: %v, want [c]", got)
+	}
+	if v, err := m.Get("c"); err != nil || v != 5 {
+		t.Errorf("Get(c) after overwrite = %d, %v", v, err)
+	}
+	m.Set("c", 6)
+	if got := rec.take(); len(got) != 0 {
+		t.Errorf("overwriting an unexpired entry reported %v", got)
+	}
+}
+
+func TestExpiringMapRemove(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	m := NewExpiringMap[string, int](time.Minute, clock)
+	var rec expiryRecorder
+	m.OnExpire(rec.record)
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+	if !m.Remove("a") || m.Remove("a") || m.Remove("missing") {
+		t.Errorf("Remove did not report presence correctly")
+	}
+
+	clock.Advance(time.Minute)
+	if m.Remove("b") {
+		t.Errorf("Remove of an expired entry returned true")
+	}
+	if got := rec.take(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("expired: %v, want [b]; removed entries should not be reported", got)
+	}
+
+	m.Set("c", 3)
+	m.Clear()
+	clock.Advance(time.Hour)
+	if m.Sweep() != 0 || !m.IsEmpty() || len(rec.take()) != 0 {
+		t.Errorf("cleared entries were swept or reported")
+	}
+}
+
+func TestExpiringMapSweep(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	m := NewExpiringMap[string, int](0, clock)
+	var rec expiryRecorder
+	m.OnExpire(rec.record)
+
+	for i, k := range []string{"a", "b", "c", "d"} {
+		m.SetWithTTL(k, i, time.Duration(i+1)*time.Second)
+	}
+	m.Set("forever", 0)              // the default TTL is 0, so never expires
+	m.SetWithTTL("b", 10, time.Hour) // the old deadline is now stale
+
+	clock.Advance(3 * time.Second)
+	if n := m.Sweep(); n != 2 {
+		t.Errorf("Sweep() removed %d entries, want 2", n)
+	}
+	if got := rec.take(); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("expired: %v, want [a c]", got)
+	}
+	entries := *m.Entries()
+	slices.SortFunc(entries, func(x, y Entry[string, int]) int { return len(x.Key) - len(y.Key) })
+	if len(entries) != 3 || entries[0].Value+entries[1].Value != 13 {
+		t.Errorf("Entries() = %v", entries)
+	}
+	if m.Sweep() != 0 {
+		t.Errorf("second Sweep() removed entries")
+	}
+}
+
+func TestExpiringMapDeadlinesBounded(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	m := NewExpiringMap[int, int](time.Minute, clock)
+	for i := 0; i < 10000; i++ {
+		// Re-setting the same few keys leaves stale deadlines behind.
+		m.Set(i%5, i)
+		if i%3 == 0 {
+			m.Remove(i % 5)
+		}
+		clock.Advance(time.Millisecond)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d, n := m.deadline.Size(), len(m.entries); d > 2*n+1 {
+		t.Errorf("%d deadlines held for %d entries", d, n)
+	}
+}
+
+func TestExpiringMapBackgroundSweeper(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	m := NewExpiringMap[string, int](time.Minute, clock)
+	expired := make(chan string, 10)
+	m.OnExpire(func(k string, _ int) { expired <- k })
+
+	m.Set("a", 1)
+	m.SetWithTTL("b", 2, 3*time.Minute)
+	m.StartSweeping(time.Minute)
+	defer m.StopSweeping()
+
+	// The first sweep expires "a".
+	clock.Advance(time.Minute)
+	if k := <-expired; k != "a" {
+		t.Fatalf("expired %q, want a", k)
+	}
+	// Wait for the sweeper to finish and wait on the clock again.
+	clock.BlockUntil(1)
+
+	clock.Advance(time.Minute)
+	clock.BlockUntil(1)
+	select {
+	case k := <-expired:
+		t.Fatalf("expired %q early", k)
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	if k := <-expired; k != "b" {
+		t.Fatalf("expired %q, want b", k)
+	}
+	clock.BlockUntil(1)
+
+	// Restarting replaces the sweeper, which still runs on the same clock.
+	m.StartSweeping(30 * time.Second)
+	m.SetWithTTL("c", 3, 30*time.Second)
+	clock.Advance(30 * time.Second)
+	if k := <-expired; k != "c" {
+		t.Fatalf("expired %q, want c", k)
+	}
+
+	if !panics(func() { m.StartSweeping(0) }) {
+		t.Errorf("expected panic for a non-positive interval")
+	}
+}
+
+func TestExpiringMapConcurrentStartSweeping(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	m := NewExpiringMap[string, int](time.Minute, clock)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.StartSweeping(time.Minute)
+		}()
+	}
+	wg.Wait()
+	m.StopSweeping()
+
+	// Every sweeper has been stopped, so after its clock fires, none of them
+	// waits on the clock again.
+	clock.Advance(time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	if n := len(clock.waiters); n != 0 {
+		t.Errorf("%d sweepers still running after StopSweeping", n)
+	}
+}
+
+func panics(f func()) (panicked bool) {
+	defer func() { panicked = recover() != nil }()
+	f()
+	return
+}
+
+func TestExpiringSet(t *testing.T) {
+	clock := NewManualClock(clockStart)
+	s := NewExpiringSet[string](time.Minute, clock)
+	var expired []string
+	s.OnExpire(func(x string) { expired = append(expired, x) })
+
+	s.Add("a")
+	s.AddWithTTL("b", 2*time.Minute)
+	s.AddWithTTL("c", 0)
+	if !s.Remove("c") || s.Remove("c") {
+		t.Errorf("Remove did not report presence correctly")
+	}
+
+	clock.Advance(time.Minute)
+	if s.Contains("a") || !s.Contains("b") || s.Size() != 1 {
+		t.Errorf("Contains(a) = %v, Contains(b) = %v, Size() = %d",
+			s.Contains("a"), s.Contains("b"), s.Size())
+	}
+	if ttl, err := s.TTL("b"); err != nil || ttl != time.Minute {
+		t.Errorf("TTL(b) = %v, %v", ttl, err)
+	}
+	if !slices.Equal(expired, []string{"a"}) {
+		t.Errorf("expired = %v, want [a]", expired)
+	}
+
+	clock.Advance(time.Minute)
+	if n := s.Sweep(); n != 1 || !s.IsEmpty() || len(s.Slice()) != 0 {
+		t.Errorf("Sweep() = %d, Slice() = %v", n, s.Slice())
+	}
+	s.OnExpire(nil)
+	s.Add("d")
+	clock.Advance(time.Minute)
+	s.Sweep() // no callback set
+}
@@ -0,0 +1,107 @@
+package collections
+
+import "time"
+
+// ExpiringSet is a set whose elements are removed once their time-to-live
+// (TTL) has elapsed. It is backed by an ExpiringMap, and is safe for
+// concurrent use.
+type ExpiringSet[T comparable] struct {
+	m *ExpiringMap[T, o]
+}
+
+// Constructors
+
+// NewExpiringSet makes a new ExpiringSet whose elements expire after the
+// given default TTL. A non-positive ttl means elements do not expire unless
+// added with AddWithTTL. If clock is nil, the system clock is used.
+func NewExpiringSet[T comparable](ttl time.Duration, clock Clock) *ExpiringSet[T] {
+	return &ExpiringSet[T]{NewExpiringMap[T, o](ttl, clock)}
+}
+
+// OnExpire sets a callback which is called with each element that expires.
+// It is not called for unexpired elements removed by Remove, or for elements
+// removed by Clear.
+func (s *ExpiringSet[T]) OnExpire(f func(t T)) {
+	if f == nil {
+		s.m.OnExpire(nil)
+		return
+	}
+	s.m.OnExpire(func(t T, _ o) { f(t) })
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of unexpired elements in this ExpiringSet.
+func (s *ExpiringSet[T]) Size() int {
+	return s.m.Size()
+}
+
+// IsEmpty returns true if this ExpiringSet has no unexpired elements.
+func (s *ExpiringSet[T]) IsEmpty() bool {
+	return s.m.IsEmpty()
+}
+
+// Contains returns true if this ExpiringSet contains t and it has not
+// expired.
+func (s *ExpiringSet[T]) Contains(t T) bool {
+	return s.m.Contains(t)
+}
+
+// TTL returns the time remaining before t expires, or a non-positive
+// duration if it never expires. It returns an error if t is not in the set
+// or has expired.
+func (s *ExpiringSet[T]) TTL(t T) (time.Duration, error) {
+	return s.m.TTL(t)
+}
+
+// Slice returns a slice of the unexpired elements in this ExpiringSet, in no
+// particular order.
+func (s *ExpiringSet[T]) Slice() []T {
+	return *s.m.Keys()
+}
+
+// Basic (mutating) functions
+
+// Add adds t to this ExpiringSet, to expire after the default TTL. If t is
+// already present, its expiry time is reset.
+func (s *ExpiringSet[T]) Add(t T) {
+	s.m.Set(t, o{})
+}
+
+// AddWithTTL adds t to this ExpiringSet, to expire after the given TTL. A
+// non-positive ttl means t does not expire.
+func (s *ExpiringSet[T]) AddWithTTL(t T, ttl time.Duration) {
+	s.m.SetWithTTL(t, o{}, ttl)
+}
+
+// Remove removes t from this ExpiringSet. It returns false if t was not in
+// the set or had already expired, and returns true if t was removed.
+func (s *ExpiringSet[T]) Remove(t T) bool {
+	return s.m.Remove(t)
+}
+
+// Clear removes all elements from this ExpiringSet.
+func (s *ExpiringSet[T]) Clear() {
+	s.m.Clear()
+}
+
+// Sweep removes all expired elements from this ExpiringSet, calling the
+// OnExpire callback for each one. It returns the number of elements removed.
+func (s *ExpiringSet[T]) Sweep() int {
+	return s.m.Sweep()
+}
+
+// Background sweeping
+
+// StartSweeping starts a goroutine which calls Sweep every interval. Any
+// sweeper already running is stopped first. As for ExpiringMap, each
+// interval starts once the previous sweep has finished.
+// It panics if interval is not positive.
+func (s *ExpiringSet[T]) StartSweeping(interval time.Duration) {
+	s.m.StartSweeping(interval)
+}
+
+// StopSweeping stops the background sweeper, if one is running.
+func (s *ExpiringSet[T]) StopSweeping() {
+	s.m.StopSweeping()
+}
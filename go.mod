@@ -1,3 +1,3 @@
 module github.com/barrettj12/collections
 
-go 1.18
+go 1.22
@@ -0,0 +1,546 @@
+package collections
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Graph is an implementation of a graph using adjacency maps. Nodes have type
+// N, and each edge carries a value of type E (e.g. a weight or a label).
+// There is at most one edge from one node to another.
+//
+// Nodes, and the neighbours of each node, are kept in insertion order, so
+// that iteration and the graph algorithms are deterministic.
+type Graph[N comparable, E any] struct {
+	directed bool
+	// adj maps each node to its out-neighbours, and the values of the edges
+	// to them. In an undirected graph, each edge is stored in both
+	// directions.
+	adj   *LinkedMap[N, *LinkedMap[N, E]]
+	edges int
+}
+
+// GraphEdge is an edge in a Graph.
+type GraphEdge[N, E any] struct {
+	From, To N
+	Value    E
+}
+
+// Constructors
+
+// NewDirectedGraph makes a new empty directed Graph with the specified
+// initial capacity (in nodes).
+func NewDirectedGraph[N comparable, E any](capacity int) *Graph[N, E] {
+	return &Graph[N, E]{directed: true, adj: NewLinkedMap[N, *LinkedMap[N, E]](capacity)}
+}
+
+// NewUndirectedGraph makes a new empty undirected Graph with the specified
+// initial capacity (in nodes).
+func NewUndirectedGraph[N comparable, E any](capacity int) *Graph[N, E] {
+	return &Graph[N, E]{directed: false, adj: NewLinkedMap[N, *LinkedMap[N, E]](capacity)}
+}
+
+// Basic (non-mutating) functions
+
+// IsDirected returns true if this Graph is directed.
+func (g *Graph[N, E]) IsDirected() bool {
+	return g.directed
+}
+
+// NodeCount returns the number of nodes in this Graph.
+func (g *Graph[N, E]) NodeCount() int {
+	return g.adj.Size()
+}
+
+// EdgeCount returns the number of edges in this Graph. In an undirected
+// Graph, each edge is counted once.
+func (g *Graph[N, E]) EdgeCount() int {
+	return g.edges
+}
+
+// HasNode returns true if n is a node in this Graph.
+func (g *Graph[N, E]) HasNode(n N) bool {
+	return g.adj.Contains(n)
+}
+
+// HasEdge returns true if there is an edge from u to v in this Graph.
+func (g *Graph[N, E]) HasEdge(u, v N) bool {
+	_, err := g.GetEdge(u, v)
+	return err == nil
+}
+
+// GetEdge returns the value of the edge from u to v. It returns an error if
+// there is no such edge.
+func (g *Graph[N, E]) GetEdge(u, v N) (e E, err error) {
+	out, err := g.adj.Get(u)
+	if err != nil {
+		err = errNodeNotFound(u)
+		return
+	}
+	e, err = out.Get(v)
+	if err != nil {
+		err = errEdgeNotFound(u, v)
+	}
+	return
+}
+
+// Nodes returns all nodes in this Graph, in insertion order.
+func (g *Graph[N, E]) Nodes() *List[N] {
+	return g.adj.Keys()
+}
+
+// Neighbors returns the nodes which are joined to n by an edge from n, in
+// the order the edges were added. It returns an error if n is not in this
+// Graph.
+func (g *Graph[N, E]) Neighbors(n N) (*List[N], error) {
+	out, err := g.adj.Get(n)
+	if err != nil {
+		return nil, errNodeNotFound(n)
+	}
+	return out.Keys(), nil
+}
+
+// Edges returns all edges in this Graph. In an undirected Graph, each edge is
+// returned once.
+func (g *Graph[N, E]) Edges() *List[GraphEdge[N, E]] {
+	edges := NewList[GraphEdge[N, E]](g.edges)
+	done := NewSet[N](0)
+	for _, entry := range *g.adj.Entries() {
+		u := entry.Key
+		for _, e := range *entry.Value.Entries() {
+			if g.directed || !done.Contains(e.Key) {
+				edges.Append(GraphEdge[N, E]{u, e.Key, e.Value})
+			}
+		}
+		done.Add(u)
+	}
+	return edges
+}
+
+// Basic (mutating) functions
+
+// AddNode adds n to this Graph, if it is not already present. It returns true
+// if n was added.
+func (g *Graph[N, E]) AddNode(n N) bool {
+	if g.HasNode(n) {
+		return false
+	}
+	g.adj.Set(n, NewLinkedMap[N, E](0))
+	return true
+}
+
+// AddEdge adds an edge from u to v with value e, adding u and v to the Graph
+// if necessary. If there is already an edge from u to v, its value is
+// overwritten.
+func (g *Graph[N, E]) AddEdge(u, v N, e E) {
+	g.AddNode(u)
+	g.AddNode(v)
+	out := g.out(u)
+	if !out.Contains(v) {
+		g.edges++
+	}
+	out.Set(v, e)
+	if !g.directed {
+		g.out(v).Set(u, e)
+	}
+}
+
+// RemoveEdge removes the edge from u to v. It returns false if there was no
+// such edge, and returns true if the edge was removed.
+func (g *Graph[N, E]) RemoveEdge(u, v N) bool {
+	if !g.HasEdge(u, v) {
+		return false
+	}
+	g.out(u).Remove(v)
+	if !g.directed {
+		g.out(v).Remove(u)
+	}
+	g.edges--
+	return true
+}
+
+// RemoveNode removes n, and all edges to or from n, from this Graph. It
+// returns false if n was not in the Graph, and returns true if n was removed.
+func (g *Graph[N, E]) RemoveNode(n N) bool {
+	if !g.HasNode(n) {
+		return false
+	}
+	for _, u := range *g.adj.Keys() {
+		g.RemoveEdge(u, n)
+	}
+	if g.directed {
+		g.edges -= g.out(n).Size()
+	}
+	g.adj.Remove(n)
+	return true
+}
+
+// Iteration
+
+type bfsIterator[N comparable, E any] struct {
+	g       *Graph[N, E]
+	queue   *Queue[N]
+	visited *Set[N]
+}
+
+func (i *bfsIterator[N, E]) HasNext() bool {
+	return !i.queue.IsEmpty()
+}
+
+func (i *bfsIterator[N, E]) Next() N {
+	n, _ := i.queue.Dequeue()
+	for _, m := range *i.g.out(n).Keys() {
+		if !i.visited.Contains(m) {
+			i.visited.Add(m)
+			i.queue.Enqueue(m)
+		}
+	}
+	return n
+}
+
+// BFS returns an Iterator over the nodes reachable from start, in
+// breadth-first order. The nodes are found lazily, so the Graph must not be
+// modified during iteration. It returns an error if start is not in this
+// Graph.
+func (g *Graph[N, E]) BFS(start N) (Iterator[N], error) {
+	if !g.HasNode(start) {
+		return nil, errNodeNotFound(start)
+	}
+	it := &bfsIterator[N, E]{g, NewQueue[N](0), NewSet[N](0)}
+	it.visited.Add(start)
+	it.queue.Enqueue(start)
+	return it, nil
+}
+
+type dfsIterator[N comparable, E any] struct {
+	g       *Graph[N, E]
+	stack   *Stack[N]
+	visited *Set[N]
+}
+
+func (i *dfsIterator[N, E]) HasNext() bool {
+	// discard nodes which were visited after being pushed
+	for !i.stack.IsEmpty() {
+		n, _ := i.stack.Peek()
+		if !i.visited.Contains(n) {
+			return true
+		}
+		i.stack.Pop()
+	}
+	return false
+}
+
+func (i *dfsIterator[N, E]) Next() N {
+	i.HasNext()
+	n, _ := i.stack.Pop()
+	i.visited.Add(n)
+	// push in reverse so the first neighbour is visited first
+	neighbors := i.g.out(n).Keys()
+	for j := neighbors.Size() - 1; j >= 0; j-- {
+		if m := (*neighbors)[j]; !i.visited.Contains(m) {
+			i.stack.Push(m)
+		}
+	}
+	return n
+}
+
+// DFS returns an Iterator over the nodes reachable from start, in
+// depth-first pre-order. The nodes are found lazily, so the Graph must not be
+// modified during iteration. It returns an error if start is not in this
+// Graph.
+func (g *Graph[N, E]) DFS(start N) (Iterator[N], error) {
+	if !g.HasNode(start) {
+		return nil, errNodeNotFound(start)
+	}
+	it := &dfsIterator[N, E]{g, NewStack[N](0), NewSet[N](0)}
+	it.stack.Push(start)
+	return it, nil
+}
+
+// Algorithms
+
+// CycleError is returned by TopologicalSort when the Graph has a cycle.
+type CycleError[N any] struct {
+	// Cycle lists the nodes of a cycle in order: there is an edge from each
+	// node to the next, and from the last node to the first.
+	Cycle *List[N]
+}
+
+func (e *CycleError[N]) Error() string {
+	nodes := make([]string, 0, e.Cycle.Size()+1)
+	for _, n := range *e.Cycle {
+		nodes = append(nodes, fmt.Sprint(n))
+	}
+	nodes = append(nodes, fmt.Sprint((*e.Cycle)[0]))
+	return "graph has a cycle: " + strings.Join(nodes, " -> ")
+}
+
+// TopologicalSort returns the nodes of this directed Graph ordered so that
+// every edge goes from an earlier node to a later one. If the Graph has a
+// cycle, it returns a *CycleError describing one of the cycles.
+// It returns an error if the Graph is undirected.
+func (g *Graph[N, E]) TopologicalSort() (*List[N], error) {
+	if !g.directed {
+		return nil, errUndirected("TopologicalSort")
+	}
+
+	// Kahn's algorithm
+	indegree := NewMap[N, int](g.NodeCount())
+	for _, e := range *g.Edges() {
+		(*indegree)[e.To]++
+	}
+	ready := NewQueue[N](0)
+	for _, n := range *g.Nodes() {
+		if indegree.GetOrDefault(n, 0) == 0 {
+			ready.Enqueue(n)
+		}
+	}
+	sorted := NewList[N](g.NodeCount())
+	for !ready.IsEmpty() {
+		n, _ := ready.Dequeue()
+		sorted.Append(n)
+		for _, m := range *g.out(n).Keys() {
+			(*indegree)[m]--
+			if (*indegree)[m] == 0 {
+				ready.Enqueue(m)
+			}
+		}
+	}
+	if sorted.Size() == g.NodeCount() {
+		return sorted, nil
+	}
+
+	// Every node left over has a predecessor which is also left over, so
+	// following predecessors must eventually repeat a node.
+	pred := NewMap[N, N](0)
+	for _, e := range *g.Edges() {
+		if (*indegree)[e.From] > 0 && (*indegree)[e.To] > 0 {
+			pred.Set(e.To, e.From)
+		}
+	}
+	var n N
+	for _, m := range *g.Nodes() {
+		if (*indegree)[m] > 0 {
+			n = m
+			break
+		}
+	}
+	seen := NewSet[N](0)
+	for !seen.Contains(n) {
+		seen.Add(n)
+		n, _ = pred.Get(n)
+	}
+	// n is on the cycle; walk back round it, then reverse.
+	cycle := NewList[N](0)
+	for m := n; ; {
+		cycle.Append(m)
+		m, _ = pred.Get(m)
+		if m == n {
+			break
+		}
+	}
+	cycle.SliceStep(-1, -cycle.Size()-1, -1)
+	return nil, &CycleError[N]{cycle}
+}
+
+// StronglyConnectedComponents returns the strongly connected components of
+// this Graph: the maximal sets of nodes such that each node can be reached
+// from every other. For an undirected Graph, these are the connected
+// components. The components are returned in reverse topological order, i.e.
+// no edge goes from a component to a later one.
+func (g *Graph[N, E]) StronglyConnectedComponents() *List[*List[N]] {
+	// Tarjan's algorithm
+	t := tarjan[N, E]{
+		g:       g,
+		index:   NewMap[N, int](g.NodeCount()),
+		lowlink: NewMap[N, int](g.NodeCount()),
+		stack:   NewStack[N](0),
+		onStack: NewSet[N](0),
+		sccs:    NewList[*List[N]](0),
+	}
+	for _, n := range *g.Nodes() {
+		if !t.index.Contains(n) {
+			t.visit(n)
+		}
+	}
+	return t.sccs
+}
+
+type tarjan[N comparable, E any] struct {
+	g              *Graph[N, E]
+	index, lowlink *Map[N, int]
+	stack          *Stack[N]
+	onStack        *Set[N]
+	sccs           *List[*List[N]]
+}
+
+func (t *tarjan[N, E]) visit(n N) {
+	i := t.index.Size()
+	t.index.Set(n, i)
+	t.lowlink.Set(n, i)
+	t.stack.Push(n)
+	t.onStack.Add(n)
+
+	for _, m := range *t.g.out(n).Keys() {
+		if !t.index.Contains(m) {
+			t.visit(m)
+			t.lowlink.Set(n, min((*t.lowlink)[n], (*t.lowlink)[m]))
+		} else if t.onStack.Contains(m) {
+			t.lowlink.Set(n, min((*t.lowlink)[n], (*t.index)[m]))
+		}
+	}
+
+	if (*t.lowlink)[n] == (*t.index)[n] {
+		scc := NewList[N](0)
+		for {
+			m, _ := t.stack.Pop()
+			t.onStack.Remove(m)
+			scc.Append(m)
+			if m == n {
+				break
+			}
+		}
+		t.sccs.Append(scc)
+	}
+}
+
+// ShortestPath finds a shortest path from src to dst using Dijkstra's
+// algorithm, where the length of each edge is weight(e) for its value e.
+// It returns the nodes of the path, from src to dst inclusive, and its total
+// length. It returns an error if either node is not in the Graph, if dst is
+// not reachable from src, or if a negative edge weight is found.
+func (g *Graph[N, E]) ShortestPath(src, dst N, weight func(E) float64) (*List[N], float64, error) {
+	return g.AStar(src, dst, weight, func(N) float64 { return 0 })
+}
+
+// AStar finds a shortest path from src to dst using the A* search algorithm,
+// where the length of each edge is weight(e) for its value e.
+// heuristic(n) must estimate the length of the shortest path from n to dst
+// without overestimating it, and must be consistent: for each edge from u to
+// v, heuristic(u) <= weight(e) + heuristic(v). A good estimate makes the
+// search faster.
+// It returns the nodes of the path, from src to dst inclusive, and its total
+// length. It returns an error if either node is not in the Graph, if dst is
+// not reachable from src, or if a negative edge weight is found.
+func (g *Graph[N, E]) AStar(src, dst N, weight func(E) float64, heuristic func(N) float64) (*List[N], float64, error) {
+	for _, n := range []N{src, dst} {
+		if !g.HasNode(n) {
+			return nil, 0, errNodeNotFound(n)
+		}
+	}
+
+	type item struct {
+		node     N
+		dist     float64 // length of best known path to node
+		estimate float64 // dist + heuristic(node)
+	}
+	dist := NewMap[N, float64](0)
+	prev := NewMap[N, N](0)
+	done := NewSet[N](0)
+	pq := newPriorityQueue(func(a, b item) bool { return a.estimate < b.estimate })
+
+	dist.Set(src, 0)
+	pq.Push(item{src, 0, heuristic(src)})
+	for !pq.IsEmpty() {
+		it := pq.Pop()
+		if done.Contains(it.node) {
+			continue // stale entry
+		}
+		if it.node == dst {
+			path := NewList[N](0)
+			for n := dst; ; n, _ = prev.Get(n) {
+				path.Append(n)
+				if n == src {
+					break
+				}
+			}
+			path.SliceStep(-1, -path.Size()-1, -1)
+			return path, it.dist, nil
+		}
+		done.Add(it.node)
+
+		for _, e := range *g.out(it.node).Entries() {
+			w := weight(e.Value)
+			if w < 0 {
+				return nil, 0, fmt.Errorf("negative weight %v on edge from %v to %v", w, it.node, e.Key)
+			}
+			d := it.dist + w
+			if d < dist.GetOrDefault(e.Key, math.Inf(1)) {
+				dist.Set(e.Key, d)
+				prev.Set(e.Key, it.node)
+				pq.Push(item{e.Key, d, d + heuristic(e.Key)})
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("no path from %v to %v", src, dst)
+}
+
+// MinimumSpanningTree returns the edges of a minimum spanning tree of this
+// undirected Graph, found using Prim's algorithm, where the length of each
+// edge is weight(e) for its value e. If the Graph is not connected, it
+// returns a minimum spanning forest, with a tree for each component.
+// It returns an error if the Graph is directed.
+func (g *Graph[N, E]) MinimumSpanningTree(weight func(E) float64) (*List[GraphEdge[N, E]], error) {
+	if g.directed {
+		return nil, errDirected("MinimumSpanningTree")
+	}
+
+	type item struct {
+		edge   GraphEdge[N, E]
+		weight float64
+	}
+	tree := NewList[GraphEdge[N, E]](g.NodeCount())
+	inTree := NewSet[N](g.NodeCount())
+	pq := newPriorityQueue(func(a, b item) bool { return a.weight < b.weight })
+	addNode := func(n N) {
+		inTree.Add(n)
+		for _, e := range *g.out(n).Entries() {
+			if !inTree.Contains(e.Key) {
+				pq.Push(item{GraphEdge[N, E]{n, e.Key, e.Value}, weight(e.Value)})
+			}
+		}
+	}
+
+	for _, root := range *g.Nodes() {
+		if inTree.Contains(root) {
+			continue
+		}
+		addNode(root)
+		for !pq.IsEmpty() {
+			it := pq.Pop()
+			if inTree.Contains(it.edge.To) {
+				continue
+			}
+			tree.Append(it.edge)
+			addNode(it.edge.To)
+		}
+	}
+	return tree, nil
+}
+
+// Internal methods
+
+// out returns the out-neighbours of n, which must be in the Graph.
+func (g *Graph[N, E]) out(n N) *LinkedMap[N, E] {
+	out, _ := g.adj.Get(n)
+	return out
+}
+
+// Errors
+
+func errNodeNotFound(n any) error {
+	return fmt.Errorf("node not found in Graph: %v", n)
+}
+
+func errEdgeNotFound(u, v any) error {
+	return fmt.Errorf("edge not found in Graph: %v -> %v", u, v)
+}
+
+func errUndirected(op string) error {
+	return fmt.Errorf("%s requires a directed Graph", op)
+}
+
+func errDirected(op string) error {
+	return fmt.Errorf("%s requires an undirected Graph", op)
+}
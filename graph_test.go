@@ -0,0 +1,469 @@
+package collections
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// randomGraph returns a graph on nodes 0..n-1 where each possible edge is
+// present with probability p, with integer weights in [1, 10].
+func randomGraph(r *rand.Rand, n int, p float64, directed bool) *Graph[int, float64] {
+	g := NewUndirectedGraph[int, float64](n)
+	if directed {
+		g = NewDirectedGraph[int, float64](n)
+	}
+	for u := 0; u < n; u++ {
+		g.AddNode(u)
+	}
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if u != v && (directed || u < v) && r.Float64() < p {
+				g.AddEdge(u, v, float64(1+r.IntN(10)))
+			}
+		}
+	}
+	return g
+}
+
+func identity(w float64) float64 { return w }
+
+// allPairs returns the shortest distances between every pair of nodes of g,
+// which must be 0..n-1, using the Floyd-Warshall algorithm. The distance is
+// +Inf if there is no path.
+func allPairs(g *Graph[int, float64]) [][]float64 {
+	n := g.NodeCount()
+	dist := make([][]float64, n)
+	for u := range dist {
+		dist[u] = make([]float64, n)
+		for v := range dist[u] {
+			dist[u][v] = math.Inf(1)
+		}
+		dist[u][u] = 0
+	}
+	for _, e := range *g.Edges() {
+		dist[e.From][e.To] = min(dist[e.From][e.To], e.Value)
+		if !g.IsDirected() {
+			dist[e.To][e.From] = dist[e.From][e.To]
+		}
+	}
+	for k := 0; k < n; k++ {
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				dist[u][v] = min(dist[u][v], dist[u][k]+dist[k][v])
+			}
+		}
+	}
+	return dist
+}
+
+func collectNodes(t *testing.T, it Iterator[int], err error) []int {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nodes []int
+	for it.HasNext() {
+		nodes = append(nodes, it.Next())
+	}
+	return nodes
+}
+
+func TestGraphBasics(t *testing.T) {
+	g := NewUndirectedGraph[string, int](0)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 2)
+	g.AddEdge("c", "b", 3) // overwrites
+	if g.EdgeCount() != 2 || g.NodeCount() != 3 {
+		t.Fatalf("EdgeCount() = %d, NodeCount() = %d", g.EdgeCount(), g.NodeCount())
+	}
+	if e, err := g.GetEdge("b", "c"); err != nil || e != 3 {
+		t.Errorf("GetEdge(b, c) = %d, %v; want 3", e, err)
+	}
+	if _, err := g.GetEdge("a", "c"); err == nil {
+		t.Errorf("expected error for a missing edge")
+	}
+	if _, err := g.Neighbors("z"); err == nil {
+		t.Errorf("expected error for a missing node")
+	}
+	if !g.RemoveNode("b") || g.EdgeCount() != 0 || g.HasEdge("a", "b") {
+		t.Errorf("RemoveNode did not remove incident edges")
+	}
+
+	d := NewDirectedGraph[string, int](0)
+	d.AddEdge("a", "b", 1)
+	d.AddEdge("b", "a", 2)
+	d.AddEdge("b", "c", 3)
+	if !d.RemoveEdge("a", "b") || d.RemoveEdge("a", "b") || !d.HasEdge("b", "a") {
+		t.Errorf("RemoveEdge on a directed Graph removed the wrong edges")
+	}
+	d.RemoveNode("b")
+	if d.EdgeCount() != 0 || !slices.Equal(*d.Nodes(), []string{"a", "c"}) {
+		t.Errorf("after RemoveNode: %d edges, nodes %v", d.EdgeCount(), *d.Nodes())
+	}
+}
+
+func TestGraphTraversalOrder(t *testing.T) {
+	//   0 -> 1 -> 3
+	//   |    |
+	//   v    v
+	//   2 -> 4    5 (unreachable)
+	g := NewDirectedGraph[int, float64](0)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(1, 4, 1)
+	g.AddEdge(2, 4, 1)
+	g.AddNode(5)
+
+	it, err := g.BFS(0)
+	if got := collectNodes(t, it, err); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("BFS(0) = %v", got)
+	}
+	it, err = g.DFS(0)
+	if got := collectNodes(t, it, err); !slices.Equal(got, []int{0, 1, 3, 4, 2}) {
+		t.Errorf("DFS(0) = %v", got)
+	}
+	if _, err := g.BFS(9); err == nil {
+		t.Errorf("expected error from BFS on a missing node")
+	}
+	if _, err := g.DFS(9); err == nil {
+		t.Errorf("expected error from DFS on a missing node")
+	}
+}
+
+func TestGraphTraversalReachability(t *testing.T) {
+	r := rand.New(seeded(40))
+	for trial := 0; trial < 50; trial++ {
+		g := randomGraph(r, 12, 0.15, trial%2 == 0)
+		dist := allPairs(g)
+		hops := allPairs(unitWeights(g))
+		for start := 0; start < g.NodeCount(); start++ {
+			var want []int
+			for v, d := range dist[start] {
+				if !math.IsInf(d, 1) {
+					want = append(want, v)
+				}
+			}
+
+			it, err := g.BFS(start)
+			bfs := collectNodes(t, it, err)
+			for i := 1; i < len(bfs); i++ {
+				if hops[start][bfs[i-1]] > hops[start][bfs[i]] {
+					t.Fatalf("BFS(%d) = %v is not in order of distance", start, bfs)
+				}
+			}
+			it, err = g.DFS(start)
+			dfs := collectNodes(t, it, err)
+			if dfs[0] != start || bfs[0] != start {
+				t.Fatalf("traversal from %d starts at %d (BFS), %d (DFS)", start, bfs[0], dfs[0])
+			}
+			slices.Sort(bfs)
+			slices.Sort(dfs)
+			if !slices.Equal(bfs, want) || !slices.Equal(dfs, want) {
+				t.Fatalf("from %d: BFS reached %v, DFS reached %v, want %v", start, bfs, dfs, want)
+			}
+		}
+	}
+}
+
+// unitWeights returns a copy of g with every edge weight 1.
+func unitWeights(g *Graph[int, float64]) *Graph[int, float64] {
+	u := NewUndirectedGraph[int, float64](g.NodeCount())
+	if g.IsDirected() {
+		u = NewDirectedGraph[int, float64](g.NodeCount())
+	}
+	for _, n := range *g.Nodes() {
+		u.AddNode(n)
+	}
+	for _, e := range *g.Edges() {
+		u.AddEdge(e.From, e.To, 1)
+	}
+	return u
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	r := rand.New(seeded(41))
+	for trial := 0; trial < 50; trial++ {
+		// A random DAG: edges only go forwards in a random permutation.
+		const n = 15
+		perm := r.Perm(n)
+		g := NewDirectedGraph[int, float64](n)
+		for i := 0; i < n; i++ {
+			g.AddNode(perm[i])
+			for j := i + 1; j < n; j++ {
+				if r.IntN(4) == 0 {
+					g.AddEdge(perm[i], perm[j], 1)
+				}
+			}
+		}
+
+		order, err := g.TopologicalSort()
+		if err != nil {
+			t.Fatalf("TopologicalSort of a DAG: %v", err)
+		}
+		if order.Size() != n {
+			t.Fatalf("TopologicalSort returned %d nodes, want %d", order.Size(), n)
+		}
+		pos := map[int]int{}
+		for i, v := range *order {
+			pos[v] = i
+		}
+		for _, e := range *g.Edges() {
+			if pos[e.From] >= pos[e.To] {
+				t.Fatalf("edge %d -> %d goes backwards in %v", e.From, e.To, *order)
+			}
+		}
+
+		// Adding a back edge creates a cycle, which must be reported.
+		u, v := perm[r.IntN(n/2)], perm[n/2+r.IntN(n/2)]
+		g.AddEdge(u, v, 1)
+		g.AddEdge(v, u, 1)
+		_, err = g.TopologicalSort()
+		var cycleErr *CycleError[int]
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("TopologicalSort of a cyclic graph returned %v", err)
+		}
+		cycle := *cycleErr.Cycle
+		for i, c := range cycle {
+			if next := cycle[(i+1)%len(cycle)]; !g.HasEdge(c, next) {
+				t.Fatalf("reported cycle %v has no edge %d -> %d", cycle, c, next)
+			}
+		}
+		if len(AsSet(cycle).Slice()) != len(cycle) {
+			t.Fatalf("reported cycle %v repeats a node", cycle)
+		}
+	}
+
+	if _, err := NewUndirectedGraph[int, int](0).TopologicalSort(); err == nil {
+		t.Errorf("expected error from TopologicalSort on an undirected Graph")
+	}
+}
+
+func TestGraphCycleErrorMessage(t *testing.T) {
+	g := NewDirectedGraph[string, int](0)
+	g.AddEdge("a", "b", 0)
+	g.AddEdge("b", "c", 0)
+	g.AddEdge("c", "a", 0)
+	_, err := g.TopologicalSort()
+	want := map[string]bool{
+		"graph has a cycle: a -> b -> c -> a": true,
+		"graph has a cycle: b -> c -> a -> b": true,
+		"graph has a cycle: c -> a -> b -> c": true,
+	}
+	if err == nil || !want[err.Error()] {
+		t.Errorf("TopologicalSort error = %v", err)
+	}
+}
+
+func TestGraphStronglyConnectedComponents(t *testing.T) {
+	r := rand.New(seeded(42))
+	for trial := 0; trial < 100; trial++ {
+		directed := trial%4 != 0
+		g := randomGraph(r, 12, 0.12, directed)
+		dist := allPairs(g)
+		reach := func(u, v int) bool { return !math.IsInf(dist[u][v], 1) }
+
+		sccs := g.StronglyConnectedComponents()
+		component := map[int]int{}
+		for i, scc := range *sccs {
+			for _, n := range *scc {
+				if _, ok := component[n]; ok {
+					t.Fatalf("node %d is in more than one component", n)
+				}
+				component[n] = i
+			}
+		}
+		if len(component) != g.NodeCount() {
+			t.Fatalf("components cover %d nodes, want %d", len(component), g.NodeCount())
+		}
+		for u := 0; u < g.NodeCount(); u++ {
+			for v := 0; v < g.NodeCount(); v++ {
+				same := reach(u, v) && reach(v, u)
+				if (component[u] == component[v]) != same {
+					t.Fatalf("nodes %d and %d: same component = %v, want %v",
+						u, v, !same, same)
+				}
+			}
+		}
+		// reverse topological order: edges never go to a later component
+		for _, e := range *g.Edges() {
+			if directed && component[e.From] < component[e.To] {
+				t.Fatalf("edge %d -> %d goes to a later component", e.From, e.To)
+			}
+		}
+	}
+}
+
+func TestGraphShortestPath(t *testing.T) {
+	r := rand.New(seeded(43))
+	for trial := 0; trial < 40; trial++ {
+		g := randomGraph(r, 10, 0.25, trial%2 == 0)
+		dist := allPairs(g)
+		for src := 0; src < g.NodeCount(); src++ {
+			for dst := 0; dst < g.NodeCount(); dst++ {
+				path, length, err := g.ShortestPath(src, dst, identity)
+				if math.IsInf(dist[src][dst], 1) {
+					if err == nil {
+						t.Fatalf("ShortestPath(%d, %d) found a path to an unreachable node", src, dst)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("ShortestPath(%d, %d): %v", src, dst, err)
+				}
+				if length != dist[src][dst] {
+					t.Fatalf("ShortestPath(%d, %d) has length %v, want %v", src, dst, length, dist[src][dst])
+				}
+				checkPath(t, g, path, src, dst, length)
+			}
+		}
+	}
+}
+
+// checkPath checks that path is a path in g from src to dst of the given
+// length.
+func checkPath(t *testing.T, g *Graph[int, float64], path *List[int], src, dst int, length float64) {
+	t.Helper()
+	p := *path
+	if p[0] != src || p[len(p)-1] != dst {
+		t.Fatalf("path %v does not go from %d to %d", p, src, dst)
+	}
+	total := 0.0
+	for i := 1; i < len(p); i++ {
+		w, err := g.GetEdge(p[i-1], p[i])
+		if err != nil {
+			t.Fatalf("path %v uses a missing edge: %v", p, err)
+		}
+		total += w
+	}
+	if total != length {
+		t.Fatalf("path %v has length %v, but %v was reported", p, total, length)
+	}
+}
+
+func TestGraphAStar(t *testing.T) {
+	// A grid with random walls, where the Manhattan distance is a
+	// consistent heuristic.
+	const size = 12
+	r := rand.New(seeded(44))
+	for trial := 0; trial < 20; trial++ {
+		g := NewUndirectedGraph[int, float64](size * size)
+		wall := func(x, y int) bool { return (x+y)%5 != 0 && r.IntN(4) == 0 }
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				g.AddNode(x*size + y)
+				if x > 0 && !wall(x, y) {
+					g.AddEdge((x-1)*size+y, x*size+y, 1)
+				}
+				if y > 0 && !wall(x, y) {
+					g.AddEdge(x*size+y-1, x*size+y, 1)
+				}
+			}
+		}
+		dist := allPairs(g)
+		dst := size*size - 1
+		manhattan := func(n int) float64 {
+			return float64(size-1-n/size) + float64(size-1-n%size)
+		}
+		for src := 0; src < size*size; src += 7 {
+			path, length, err := g.AStar(src, dst, identity, manhattan)
+			if math.IsInf(dist[src][dst], 1) {
+				if err == nil {
+					t.Fatalf("AStar(%d, %d) found a path to an unreachable node", src, dst)
+				}
+				continue
+			}
+			if err != nil || length != dist[src][dst] {
+				t.Fatalf("AStar(%d, %d) = %v, %v; want length %v", src, dst, length, err, dist[src][dst])
+			}
+			checkPath(t, g, path, src, dst, length)
+		}
+	}
+}
+
+func TestGraphShortestPathErrors(t *testing.T) {
+	g := NewDirectedGraph[int, float64](0)
+	g.AddEdge(0, 1, -1)
+	g.AddNode(2)
+	if _, _, err := g.ShortestPath(0, 1, identity); err == nil {
+		t.Errorf("expected error for a negative weight")
+	}
+	if _, _, err := g.ShortestPath(0, 2, identity); err == nil {
+		t.Errorf("expected error for an unreachable node")
+	}
+	if _, _, err := g.ShortestPath(0, 9, identity); err == nil {
+		t.Errorf("expected error for a missing node")
+	}
+	if path, length, err := g.ShortestPath(2, 2, identity); err != nil || length != 0 || path.Size() != 1 {
+		t.Errorf("ShortestPath(2, 2) = %v, %v, %v", path, length, err)
+	}
+}
+
+// kruskalWeight returns the total weight and number of edges of a minimum
+// spanning forest of g, found by Kruskal's algorithm.
+func kruskalWeight(g *Graph[int, float64]) (float64, int) {
+	edges := *g.Edges()
+	slices.SortFunc(edges, func(a, b GraphEdge[int, float64]) int {
+		return int(a.Value - b.Value)
+	})
+	parent := make([]int, g.NodeCount())
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	total, count := 0.0, 0
+	for _, e := range edges {
+		if a, b := find(e.From), find(e.To); a != b {
+			parent[a] = b
+			total += e.Value
+			count++
+		}
+	}
+	return total, count
+}
+
+func TestGraphMinimumSpanningTree(t *testing.T) {
+	r := rand.New(seeded(45))
+	for trial := 0; trial < 100; trial++ {
+		g := randomGraph(r, 15, 0.2, false)
+		tree, err := g.MinimumSpanningTree(identity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantWeight, wantCount := kruskalWeight(g)
+		weight := 0.0
+		forest := NewUndirectedGraph[int, float64](g.NodeCount())
+		for _, n := range *g.Nodes() {
+			forest.AddNode(n)
+		}
+		for _, e := range *tree {
+			if w, err := g.GetEdge(e.From, e.To); err != nil || w != e.Value {
+				t.Fatalf("tree edge %v is not in the graph", e)
+			}
+			weight += e.Value
+			forest.AddEdge(e.From, e.To, e.Value)
+		}
+		if tree.Size() != wantCount || weight != wantWeight {
+			t.Fatalf("spanning forest has %d edges of weight %v, want %d of weight %v",
+				tree.Size(), weight, wantCount, wantWeight)
+		}
+		// A forest has exactly one fewer edge than nodes in each component.
+		if c := forest.StronglyConnectedComponents().Size(); tree.Size() != g.NodeCount()-c {
+			t.Fatalf("spanning forest has %d edges and %d components, so has a cycle",
+				tree.Size(), c)
+		}
+	}
+
+	if _, err := NewDirectedGraph[int, float64](0).MinimumSpanningTree(identity); err == nil {
+		t.Errorf("expected error from MinimumSpanningTree on a directed Graph")
+	}
+}
@@ -0,0 +1,390 @@
+package collections
+
+import "fmt"
+
+// Grid is an implementation of a fixed-size two-dimensional grid, stored in
+// row-major order in a slice. Rows and columns are indexed from 0. Like
+// List.Get, methods which take a row or column index also accept negative
+// indices, counting back from the last row or column, so (-1, -1) is the
+// bottom-right cell. A Point passed to FloodFill may also hold negative
+// indices, but InBounds and Neighbors only accept non-negative ones.
+type Grid[T any] struct {
+	rows, cols int
+	cells      []T
+}
+
+// GridColumn is a view of a single column of a Grid, returned by
+// Grid.Column. Changes made through the view are reflected in the Grid, and
+// vice versa.
+type GridColumn[T any] struct {
+	g   *Grid[T]
+	col int
+}
+
+// Point is the position of a cell in a Grid.
+type Point struct {
+	Row, Col int
+}
+
+// Constructors
+
+// NewGrid makes a new Grid with the given number of rows and columns, where
+// every cell holds the zero value of T.
+// It panics if rows or cols is negative.
+func NewGrid[T any](rows, cols int) *Grid[T] {
+	if rows < 0 || cols < 0 {
+		panic(fmt.Sprintf("negative Grid dimensions %dx%d", rows, cols))
+	}
+	return &Grid[T]{rows, cols, make([]T, rows*cols)}
+}
+
+// AsGrid returns a Grid containing a copy of the given rows. It returns an
+// error if the rows have different lengths.
+func AsGrid[T any](rows [][]T) (*Grid[T], error) {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	g := NewGrid[T](len(rows), cols)
+	for r, row := range rows {
+		if len(row) != cols {
+			return nil, fmt.Errorf("row %d has length %d, expected %d", r, len(row), cols)
+		}
+		copy(g.cells[r*cols:], row)
+	}
+	return g, nil
+}
+
+// AsSlices returns a copy of the contents of this Grid as a slice of rows.
+func (g *Grid[T]) AsSlices() [][]T {
+	rows := make([][]T, g.rows)
+	for r := range rows {
+		rows[r] = make([]T, g.cols)
+		copy(rows[r], g.cells[r*g.cols:])
+	}
+	return rows
+}
+
+// Basic (non-mutating) functions
+
+// Rows returns the number of rows in this Grid.
+func (g *Grid[T]) Rows() int {
+	return g.rows
+}
+
+// Cols returns the number of columns in this Grid.
+func (g *Grid[T]) Cols() int {
+	return g.cols
+}
+
+// Size returns the number of cells in this Grid.
+func (g *Grid[T]) Size() int {
+	return len(g.cells)
+}
+
+// InBounds returns true if p is the position of a cell in this Grid. Unlike
+// Get, it does not accept negative indices.
+func (g *Grid[T]) InBounds(p Point) bool {
+	return p.Row >= 0 && p.Row < g.rows && p.Col >= 0 && p.Col < g.cols
+}
+
+// Get returns the value of the cell at the given row and column, which may
+// be negative. It returns an error if the position is out of bounds.
+func (g *Grid[T]) Get(row, col int) (t T, err error) {
+	p, err := g.normalisePoint(row, col)
+	if err != nil {
+		return
+	}
+	return g.cells[p.Row*g.cols+p.Col], nil
+}
+
+// Row returns a view of the given row as a List. Changes to elements of the
+// List are reflected in the Grid, and vice versa; appending to the List
+// copies it and detaches it from the Grid.
+// It returns an error if the row index is out of bounds.
+func (g *Grid[T]) Row(row int) (*List[T], error) {
+	row, ok := normaliseGridIndex(row, g.rows)
+	if !ok {
+		return nil, g.errRowOutOfBounds(row)
+	}
+	start, end := row*g.cols, (row+1)*g.cols
+	return AsList(g.cells[start:end:end]), nil
+}
+
+// Column returns a view of the given column. Since a Grid is stored in
+// row-major order, a column is not contiguous, so unlike Row this returns a
+// GridColumn rather than a List; use GridColumn.List for a copy as a List.
+// It returns an error if the column index is out of bounds.
+func (g *Grid[T]) Column(col int) (*GridColumn[T], error) {
+	col, ok := normaliseGridIndex(col, g.cols)
+	if !ok {
+		return nil, g.errColOutOfBounds(col)
+	}
+	return &GridColumn[T]{g, col}, nil
+}
+
+// Neighbors returns the positions of the cells adjacent to p which are
+// within this Grid. If diagonal is false, only the 4 orthogonally adjacent
+// cells are considered; otherwise, all 8 surrounding cells are. Positions are
+// returned in row-major order.
+func (g *Grid[T]) Neighbors(p Point, diagonal bool) *List[Point] {
+	neighbors := NewList[Point](8)
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			if (dr == 0 && dc == 0) || (!diagonal && dr != 0 && dc != 0) {
+				continue
+			}
+			if q := (Point{p.Row + dr, p.Col + dc}); g.InBounds(q) {
+				neighbors.Append(q)
+			}
+		}
+	}
+	return neighbors
+}
+
+// Basic (mutating) functions
+
+// Set replaces the value of the cell at the given row and column, which may
+// be negative, with t. It returns an error if the position is out of bounds.
+func (g *Grid[T]) Set(row, col int, t T) error {
+	p, err := g.normalisePoint(row, col)
+	if err != nil {
+		return err
+	}
+	g.cells[p.Row*g.cols+p.Col] = t
+	return nil
+}
+
+// Fill sets every cell of this Grid to t.
+func (g *Grid[T]) Fill(t T) {
+	for i := range g.cells {
+		g.cells[i] = t
+	}
+}
+
+// FloodFill sets the value of the cell at p, and of every cell connected to
+// it (orthogonally) through cells with an equal value, to t. Values are
+// compared using eq. It returns the number of cells filled, or an error if p
+// is out of bounds. Like Get, it accepts negative indices in p.
+func (g *Grid[T]) FloodFill(p Point, t T, eq func(s, t T) bool) (int, error) {
+	p, err := g.normalisePoint(p.Row, p.Col)
+	if err != nil {
+		return 0, err
+	}
+	target := g.cells[p.Row*g.cols+p.Col]
+
+	filled := 0
+	visited := NewSet[Point](0)
+	queue := NewQueue[Point](0)
+	visited.Add(p)
+	queue.Enqueue(p)
+	for !queue.IsEmpty() {
+		q, _ := queue.Dequeue()
+		g.cells[q.Row*g.cols+q.Col] = t
+		filled++
+		for _, n := range *g.Neighbors(q, false) {
+			if !visited.Contains(n) && eq(g.cells[n.Row*g.cols+n.Col], target) {
+				visited.Add(n)
+				queue.Enqueue(n)
+			}
+		}
+	}
+	return filled, nil
+}
+
+// Copying functions
+
+// Copy returns a copy of the given Grid.
+func (g *Grid[T]) Copy() *Grid[T] {
+	cells := make([]T, len(g.cells))
+	copy(cells, g.cells)
+	return &Grid[T]{g.rows, g.cols, cells}
+}
+
+// SubGrid returns a copy of the part of this Grid with the given number of
+// rows and columns, whose top-left cell is at (row, col). Like Get, it
+// accepts negative indices for row and col.
+// It returns an error if rows or cols is negative, if (row, col) is out of
+// bounds (even if the sub-grid is empty), or if any part of the sub-grid is
+// out of bounds.
+func (g *Grid[T]) SubGrid(row, col, rows, cols int) (*Grid[T], error) {
+	if rows < 0 || cols < 0 {
+		return nil, fmt.Errorf("negative Grid dimensions %dx%d", rows, cols)
+	}
+	p, err := g.normalisePoint(row, col)
+	if err != nil {
+		return nil, err
+	}
+	row, col = p.Row, p.Col
+	if row+rows > g.rows || col+cols > g.cols {
+		return nil, g.errCellOutOfBounds(row+rows-1, col+cols-1)
+	}
+
+	sub := NewGrid[T](rows, cols)
+	for r := 0; r < rows; r++ {
+		start := (row+r)*g.cols + col
+		copy(sub.cells[r*cols:(r+1)*cols], g.cells[start:start+cols])
+	}
+	return sub, nil
+}
+
+// Transpose returns a new Grid which is the transpose of this Grid, i.e.
+// the cell at (r, c) is moved to (c, r).
+func (g *Grid[T]) Transpose() *Grid[T] {
+	t := NewGrid[T](g.cols, g.rows)
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			t.cells[c*t.cols+r] = g.cells[r*g.cols+c]
+		}
+	}
+	return t
+}
+
+// Rotate returns a new Grid which is this Grid rotated clockwise by the given
+// number of quarter turns. A negative number rotates anticlockwise.
+func (g *Grid[T]) Rotate(quarterTurns int) *Grid[T] {
+	switch ((quarterTurns % 4) + 4) % 4 {
+	case 1:
+		// clockwise: (r, c) -> (c, rows-1-r)
+		t := NewGrid[T](g.cols, g.rows)
+		for r := 0; r < g.rows; r++ {
+			for c := 0; c < g.cols; c++ {
+				t.cells[c*t.cols+(g.rows-1-r)] = g.cells[r*g.cols+c]
+			}
+		}
+		return t
+	case 2:
+		t := NewGrid[T](g.rows, g.cols)
+		for i, cell := range g.cells {
+			t.cells[len(t.cells)-1-i] = cell
+		}
+		return t
+	case 3:
+		// anticlockwise: (r, c) -> (cols-1-c, r)
+		t := NewGrid[T](g.cols, g.rows)
+		for r := 0; r < g.rows; r++ {
+			for c := 0; c < g.cols; c++ {
+				t.cells[(g.cols-1-c)*t.cols+r] = g.cells[r*g.cols+c]
+			}
+		}
+		return t
+	default:
+		return g.Copy()
+	}
+}
+
+// Iteration
+
+type gridIterator[T any] struct {
+	g     *Grid[T]
+	index int
+}
+
+func (i *gridIterator[T]) HasNext() bool {
+	return i.index < len(i.g.cells)
+}
+
+func (i *gridIterator[T]) Next() (Point, T) {
+	p := Point{i.index / i.g.cols, i.index % i.g.cols}
+	t := i.g.cells[i.index]
+	i.index++
+	return p, t
+}
+
+// Iterate returns an Iterator2 over the positions and values of the cells in
+// this Grid, in row-major order.
+func (g *Grid[T]) Iterate() Iterator2[Point, T] {
+	return &gridIterator[T]{g, 0}
+}
+
+// Column views
+
+// Size returns the number of cells in this column, i.e. the number of rows
+// in the Grid.
+func (c *GridColumn[T]) Size() int {
+	return c.g.rows
+}
+
+// Get returns the value of the cell in the given row of this column. Like
+// List.Get, it accepts negative indices, and returns an error if the index
+// is out of bounds.
+func (c *GridColumn[T]) Get(row int) (T, error) {
+	return c.g.Get(row, c.col)
+}
+
+// Set replaces the value of the cell in the given row of this column with t.
+// Like List.Set, it accepts negative indices, and returns an error if the
+// index is out of bounds.
+func (c *GridColumn[T]) Set(row int, t T) error {
+	return c.g.Set(row, c.col, t)
+}
+
+// List returns a copy of this column as a List.
+func (c *GridColumn[T]) List() *List[T] {
+	column := NewList[T](c.g.rows)
+	for r := 0; r < c.g.rows; r++ {
+		column.Append(c.g.cells[r*c.g.cols+c.col])
+	}
+	return column
+}
+
+type gridColumnIterator[T any] struct {
+	c   *GridColumn[T]
+	row int
+}
+
+func (i *gridColumnIterator[T]) HasNext() bool {
+	return i.row < i.c.g.rows
+}
+
+func (i *gridColumnIterator[T]) Next() T {
+	t := i.c.g.cells[i.row*i.c.g.cols+i.c.col]
+	i.row++
+	return t
+}
+
+// Iterate returns an Iterator over the values of the cells in this column,
+// from top to bottom. Values are read from the Grid lazily.
+func (c *GridColumn[T]) Iterate() Iterator[T] {
+	return &gridColumnIterator[T]{c, 0}
+}
+
+// Internal methods
+
+// normalisePoint converts a row and column, which may be negative, to a
+// Point in this Grid.
+func (g *Grid[T]) normalisePoint(row, col int) (Point, error) {
+	r, rowOK := normaliseGridIndex(row, g.rows)
+	c, colOK := normaliseGridIndex(col, g.cols)
+	if !rowOK || !colOK {
+		return Point{}, g.errCellOutOfBounds(row, col)
+	}
+	return Point{r, c}, nil
+}
+
+// normaliseGridIndex converts a row or column index in [-n, n) to one in
+// [0, n), in the same way as List.Get. It returns false if i is out of
+// bounds.
+func normaliseGridIndex(i, n int) (int, bool) {
+	if i < -n || i >= n {
+		return i, false
+	}
+	if i < 0 {
+		i += n
+	}
+	return i, true
+}
+
+// Errors
+
+func (g *Grid[T]) errCellOutOfBounds(row, col int) error {
+	return fmt.Errorf("cell (%d, %d) out of bounds in Grid (size %dx%d)", row, col, g.rows, g.cols)
+}
+
+func (g *Grid[T]) errRowOutOfBounds(row int) error {
+	return fmt.Errorf("row %d out of bounds in Grid (size %dx%d)", row, g.rows, g.cols)
+}
+
+func (g *Grid[T]) errColOutOfBounds(col int) error {
+	return fmt.Errorf("column %d out of bounds in Grid (size %dx%d)", col, g.rows, g.cols)
+}
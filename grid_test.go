@@ -0,0 +1,258 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+// numberedGrid returns a rows x cols Grid where the cell at (r, c) holds
+// 10*r + c.
+func numberedGrid(rows, cols int) *Grid[int] {
+	g := NewGrid[int](rows, cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			g.Set(r, c, 10*r+c)
+		}
+	}
+	return g
+}
+
+func TestGridIndices(t *testing.T) {
+	g := numberedGrid(2, 3)
+	tests := []struct {
+		row, col int
+		valid    bool
+		want     int
+	}{
+		{0, 0, true, 0},
+		{1, 2, true, 12},
+		{-1, -1, true, 12},
+		{-2, -3, true, 0},
+		{-1, 0, true, 10},
+		{0, -2, true, 1},
+		{2, 0, false, 0},
+		{0, 3, false, 0},
+		{-3, 0, false, 0},
+		{0, -4, false, 0},
+	}
+	for _, tt := range tests {
+		got, err := g.Get(tt.row, tt.col)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("Get(%d, %d) = %d, %v; want %d, valid %v", tt.row, tt.col, got, err, tt.want, tt.valid)
+		}
+		if err := g.Set(tt.row, tt.col, -1); (err == nil) != tt.valid {
+			t.Errorf("Set(%d, %d) error = %v, want valid %v", tt.row, tt.col, err, tt.valid)
+		}
+		if tt.valid {
+			if got, _ := g.Get(tt.row, tt.col); got != -1 {
+				t.Errorf("Get(%d, %d) = %d after Set", tt.row, tt.col, got)
+			}
+			g.Set(tt.row, tt.col, tt.want)
+		}
+	}
+	if g.InBounds(Point{-1, 0}) {
+		t.Errorf("InBounds should not accept negative indices")
+	}
+}
+
+func TestGridRowAndColumnViews(t *testing.T) {
+	g := numberedGrid(3, 4)
+
+	row, err := g.Row(-2)
+	if err != nil || !slices.Equal(*row, []int{10, 11, 12, 13}) {
+		t.Fatalf("Row(-2) = %v, %v", row, err)
+	}
+	row.Set(0, 100)
+	if v, _ := g.Get(1, 0); v != 100 {
+		t.Errorf("change to Row view not reflected in Grid")
+	}
+	g.Set(1, 3, 103)
+	if v, _ := row.Get(-1); v != 103 {
+		t.Errorf("change to Grid not reflected in Row view")
+	}
+	row.Append(999) // detaches the view
+	if v, _ := g.Get(2, 0); v != 20 {
+		t.Errorf("appending to a Row view overwrote the next row")
+	}
+
+	col, err := g.Column(-1)
+	if err != nil || col.Size() != 3 || !slices.Equal(*col.List(), []int{3, 103, 23}) {
+		t.Fatalf("Column(-1) = %v, %v", col.List(), err)
+	}
+	if err := col.Set(-1, 200); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g.Get(2, 3); v != 200 {
+		t.Errorf("change to Column view not reflected in Grid")
+	}
+	g.Set(0, 3, 300)
+	if v, _ := col.Get(0); v != 300 {
+		t.Errorf("change to Grid not reflected in Column view")
+	}
+	if _, err := col.Get(3); err == nil {
+		t.Errorf("expected error from Column view Get out of bounds")
+	}
+	if err := col.Set(-4, 0); err == nil {
+		t.Errorf("expected error from Column view Set out of bounds")
+	}
+
+	var values []int
+	for it := col.Iterate(); it.HasNext(); {
+		values = append(values, it.Next())
+	}
+	if !slices.Equal(values, []int{300, 103, 200}) {
+		t.Errorf("Column view Iterate() = %v", values)
+	}
+
+	for _, i := range []int{3, -4} {
+		if _, err := g.Row(i); err == nil {
+			t.Errorf("expected error from Row(%d)", i)
+		}
+	}
+	for _, i := range []int{4, -5} {
+		if _, err := g.Column(i); err == nil {
+			t.Errorf("expected error from Column(%d)", i)
+		}
+	}
+}
+
+func TestGridConversions(t *testing.T) {
+	rows := [][]int{{1, 2, 3}, {4, 5, 6}}
+	g, err := AsGrid(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Rows() != 2 || g.Cols() != 3 || g.Size() != 6 {
+		t.Errorf("dimensions %dx%d, size %d", g.Rows(), g.Cols(), g.Size())
+	}
+	rows[0][0] = 100
+	if got := g.AsSlices(); !slices.EqualFunc(got, [][]int{{1, 2, 3}, {4, 5, 6}}, slices.Equal) {
+		t.Errorf("AsSlices() = %v; AsGrid should copy its input", got)
+	}
+	if _, err := AsGrid([][]int{{1, 2}, {3}}); err == nil {
+		t.Errorf("expected error for ragged rows")
+	}
+	if g, err := AsGrid[int](nil); err != nil || g.Size() != 0 {
+		t.Errorf("AsGrid(nil) = %v, %v", g, err)
+	}
+
+	var cells []int
+	var points []Point
+	for it := g.Iterate(); it.HasNext(); {
+		p, v := it.Next()
+		points = append(points, p)
+		cells = append(cells, v)
+	}
+	if !slices.Equal(cells, []int{1, 2, 3, 4, 5, 6}) || points[4] != (Point{1, 1}) {
+		t.Errorf("Iterate() gave %v at %v", cells, points)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for negative dimensions")
+		}
+	}()
+	NewGrid[int](-1, 2)
+}
+
+func TestGridTransforms(t *testing.T) {
+	g, _ := AsGrid([][]int{{1, 2, 3}, {4, 5, 6}})
+	tests := []struct {
+		name string
+		got  *Grid[int]
+		want [][]int
+	}{
+		{"Transpose", g.Transpose(), [][]int{{1, 4}, {2, 5}, {3, 6}}},
+		{"Rotate(1)", g.Rotate(1), [][]int{{4, 1}, {5, 2}, {6, 3}}},
+		{"Rotate(2)", g.Rotate(2), [][]int{{6, 5, 4}, {3, 2, 1}}},
+		{"Rotate(3)", g.Rotate(3), [][]int{{3, 6}, {2, 5}, {1, 4}}},
+		{"Rotate(-1)", g.Rotate(-1), [][]int{{3, 6}, {2, 5}, {1, 4}}},
+		{"Rotate(4)", g.Rotate(4), [][]int{{1, 2, 3}, {4, 5, 6}}},
+	}
+	for _, tt := range tests {
+		if got := tt.got.AsSlices(); !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	sub, err := numberedGrid(4, 5).SubGrid(1, 2, 2, 3)
+	if err != nil || !slices.EqualFunc(sub.AsSlices(), [][]int{{12, 13, 14}, {22, 23, 24}}, slices.Equal) {
+		t.Errorf("SubGrid(1, 2, 2, 3) = %v, %v", sub.AsSlices(), err)
+	}
+	subTests := []struct {
+		row, col, rows, cols int
+		valid                bool
+		want                 [][]int
+	}{
+		{-1, -2, 1, 2, true, [][]int{{33, 34}}},
+		{-4, 0, 2, 1, true, [][]int{{0}, {10}}},
+		{1, 1, 0, 3, true, [][]int{}},
+		{1, 1, 2, 0, true, [][]int{{}, {}}},
+		{-1, -1, 0, 0, true, [][]int{}},
+		{3, 0, 2, 1, false, nil},
+		{0, 4, 1, 2, false, nil},
+		{0, 0, -1, 1, false, nil},
+		{-5, 0, 2, 0, false, nil},
+		{10, 0, 1, 0, false, nil},
+		{0, 5, 0, 0, false, nil},
+	}
+	for _, tt := range subTests {
+		sub, err := numberedGrid(4, 5).SubGrid(tt.row, tt.col, tt.rows, tt.cols)
+		if (err == nil) != tt.valid {
+			t.Errorf("SubGrid(%d, %d, %d, %d) error = %v, want valid %v", tt.row, tt.col, tt.rows, tt.cols, err, tt.valid)
+			continue
+		}
+		if tt.valid && (sub.Rows() != tt.rows || sub.Cols() != tt.cols ||
+			!slices.EqualFunc(sub.AsSlices(), tt.want, slices.Equal)) {
+			t.Errorf("SubGrid(%d, %d, %d, %d) = %v, want %v", tt.row, tt.col, tt.rows, tt.cols, sub.AsSlices(), tt.want)
+		}
+	}
+
+	cp := g.Copy()
+	cp.Set(0, 0, 100)
+	if v, _ := g.Get(0, 0); v != 1 {
+		t.Errorf("modifying a copy changed the original")
+	}
+}
+
+func TestGridNeighborsAndFloodFill(t *testing.T) {
+	g := NewGrid[int](3, 3)
+	if got := *g.Neighbors(Point{0, 0}, false); !slices.Equal(got, []Point{{0, 1}, {1, 0}}) {
+		t.Errorf("Neighbors(corner, false) = %v", got)
+	}
+	if got := g.Neighbors(Point{1, 1}, true).Size(); got != 8 {
+		t.Errorf("Neighbors(centre, true) has %d points, want 8", got)
+	}
+
+	g, _ = AsGrid([][]int{
+		{0, 0, 1, 0},
+		{1, 0, 1, 0},
+		{0, 0, 1, 1},
+		{1, 1, 0, 0},
+	})
+	eq := func(a, b int) bool { return a == b }
+	n, err := g.FloodFill(Point{0, 0}, 7, eq)
+	if err != nil || n != 5 {
+		t.Errorf("FloodFill(0, 0) filled %d cells, %v; want 5", n, err)
+	}
+	want := [][]int{
+		{7, 7, 1, 0},
+		{1, 7, 1, 0},
+		{7, 7, 1, 1},
+		{1, 1, 0, 0},
+	}
+	if got := g.AsSlices(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("after FloodFill: %v", got)
+	}
+	// diagonal cells are not connected
+	if n, err := g.FloodFill(Point{-1, -1}, 8, eq); err != nil || n != 2 {
+		t.Errorf("FloodFill(-1, -1) filled %d cells, %v; want 2", n, err)
+	}
+	// filling with the same value terminates
+	if n, _ := g.FloodFill(Point{0, 2}, 1, eq); n != 4 {
+		t.Errorf("FloodFill with the same value filled %d cells, want 4", n)
+	}
+	if _, err := g.FloodFill(Point{4, 0}, 0, eq); err == nil {
+		t.Errorf("expected error from FloodFill out of bounds")
+	}
+}
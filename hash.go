@@ -0,0 +1,157 @@
+package collections
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Hasher is a function which hashes values of type T to 64 bits. It is used by
+// the probabilistic collections (e.g. BloomFilter), which require a good
+// spread of hash values: small changes to the input should change the output
+// unpredictably.
+//
+// A Hasher must be deterministic, so that the same value always hashes to the
+// same result, including across processes if the collection is persisted.
+//
+// Constructors which take a Hasher accept nil to select a default based on
+// the kind of T: HashString for string kinds, HashBytes for byte slices,
+// HashInt for integer kinds and HashFloat for float64 kinds. This includes
+// named types such as String. For any other T, a Hasher must be given.
+type Hasher[T any] func(T) uint64
+
+// HashString is a Hasher for strings, using 64-bit FNV-1a with a finalising
+// mix step.
+func HashString(s string) uint64 {
+	h := uint64(fnvOffset)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return mix64(h)
+}
+
+// HashBytes is a Hasher for byte slices. It gives the same result as
+// HashString on the equivalent string.
+func HashBytes(b []byte) uint64 {
+	h := uint64(fnvOffset)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime
+	}
+	return mix64(h)
+}
+
+// Integer is a constraint permitting any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// HashInt is a Hasher for integers.
+func HashInt[T Integer](t T) uint64 {
+	return mix64(uint64(t))
+}
+
+// HashFloat is a Hasher for float64s. All NaN values hash the same, and 0 and
+// -0 hash the same.
+func HashFloat(f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+	if math.IsNaN(f) {
+		f = math.NaN()
+	}
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+	return HashBytes(b[:])
+}
+
+// hasherOrDefault returns hasher if it is non-nil, and otherwise the default
+// Hasher for T described on Hasher. It panics if T has no default.
+func hasherOrDefault[T any](hasher Hasher[T]) Hasher[T] {
+	if hasher != nil {
+		return hasher
+	}
+
+	// Built-in types avoid the cost of reflection on each call.
+	var h any
+	var zero T
+	switch any(zero).(type) {
+	case string:
+		h = Hasher[string](HashString)
+	case []byte:
+		h = Hasher[[]byte](HashBytes)
+	case int:
+		h = Hasher[int](HashInt[int])
+	case int8:
+		h = Hasher[int8](HashInt[int8])
+	case int16:
+		h = Hasher[int16](HashInt[int16])
+	case int32:
+		h = Hasher[int32](HashInt[int32])
+	case int64:
+		h = Hasher[int64](HashInt[int64])
+	case uint:
+		h = Hasher[uint](HashInt[uint])
+	case uint8:
+		h = Hasher[uint8](HashInt[uint8])
+	case uint16:
+		h = Hasher[uint16](HashInt[uint16])
+	case uint32:
+		h = Hasher[uint32](HashInt[uint32])
+	case uint64:
+		h = Hasher[uint64](HashInt[uint64])
+	case uintptr:
+		h = Hasher[uintptr](HashInt[uintptr])
+	case float64:
+		h = Hasher[float64](HashFloat)
+	}
+	if h != nil {
+		return h.(Hasher[T])
+	}
+
+	// Other types, including named types, are hashed according to their
+	// kind. Signed integers are sign-extended, so they hash the same as with
+	// HashInt.
+	typ := reflect.TypeFor[T]()
+	switch typ.Kind() {
+	case reflect.String:
+		return func(t T) uint64 { return HashString(reflect.ValueOf(t).String()) }
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return func(t T) uint64 { return HashBytes(reflect.ValueOf(t).Bytes()) }
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(t T) uint64 { return HashInt(reflect.ValueOf(t).Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(t T) uint64 { return HashInt(reflect.ValueOf(t).Uint()) }
+	case reflect.Float64:
+		return func(t T) uint64 { return HashFloat(reflect.ValueOf(t).Float()) }
+	}
+	panic(fmt.Sprintf("no default Hasher for type %v: a non-nil hasher is required", typ))
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// mix64 is the finaliser from the SplitMix64 generator, which spreads the
+// bits of x over the whole output.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// hashPair derives two hashes from a single hash h, so that the family of
+// hashes h1 + i*h2 can be used in place of many independent hash functions
+// (Kirsch and Mitzenmacher, 2006).
+func hashPair(h uint64) (h1, h2 uint64) {
+	return h, mix64(h^0x9e3779b97f4a7c15) | 1
+}
@@ -0,0 +1,78 @@
+package collections
+
+// priorityQueue is a binary min-heap, ordered by the given less function.
+type priorityQueue[T any] struct {
+	elems []T
+	less  func(s, t T) bool
+}
+
+func newPriorityQueue[T any](less func(s, t T) bool) *priorityQueue[T] {
+	return &priorityQueue[T]{less: less}
+}
+
+func (pq *priorityQueue[T]) Size() int {
+	return len(pq.elems)
+}
+
+func (pq *priorityQueue[T]) IsEmpty() bool {
+	return pq.Size() == 0
+}
+
+// Push adds t to the heap.
+func (pq *priorityQueue[T]) Push(t T) {
+	pq.elems = append(pq.elems, t)
+	// sift up
+	i := len(pq.elems) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.elems[i], pq.elems[parent]) {
+			break
+		}
+		pq.elems[i], pq.elems[parent] = pq.elems[parent], pq.elems[i]
+		i = parent
+	}
+}
+
+// Pop removes and returns the smallest element. The heap must be non-empty.
+func (pq *priorityQueue[T]) Pop() T {
+	top := pq.elems[0]
+	last := len(pq.elems) - 1
+	pq.elems[0] = pq.elems[last]
+	var z T
+	pq.elems[last] = z
+	pq.elems = pq.elems[:last]
+	pq.siftDown(0)
+	return top
+}
+
+// RemoveIf removes all elements for which f returns true, in linear time.
+func (pq *priorityQueue[T]) RemoveIf(f func(T) bool) {
+	kept := pq.elems[:0]
+	for _, t := range pq.elems {
+		if !f(t) {
+			kept = append(kept, t)
+		}
+	}
+	clear(pq.elems[len(kept):])
+	pq.elems = kept
+	// restore the heap property bottom-up
+	for i := len(pq.elems)/2 - 1; i >= 0; i-- {
+		pq.siftDown(i)
+	}
+}
+
+func (pq *priorityQueue[T]) siftDown(i int) {
+	for {
+		smallest := i
+		for _, c := range []int{2*i + 1, 2*i + 2} {
+			if c < len(pq.elems) && pq.less(pq.elems[c], pq.elems[smallest]) {
+				smallest = c
+			}
+		}
+		if smallest == i {
+			return
+		}
+		pq.elems[i], pq.elems[smallest] = pq.elems[smallest], pq.elems[i]
+		i = smallest
+	}
+}
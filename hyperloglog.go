@@ -0,0 +1,132 @@
+package collections
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// HyperLogLog estimates the number of distinct elements added to it, using a
+// small, fixed amount of memory regardless of how many elements are added.
+// HyperLogLogs with the same parameters and hasher can be merged, e.g. to
+// combine counts computed on different machines.
+type HyperLogLog[T any] struct {
+	registers []uint8
+	p         uint8 // precision: there are 2^p registers
+	hasher    Hasher[T]
+}
+
+// Precision limits for a HyperLogLog.
+const (
+	minHLLPrecision = 4
+	maxHLLPrecision = 18
+)
+
+// Constructors
+
+// NewHyperLogLog makes a new HyperLogLog whose estimates have a relative
+// standard error of at most stdError, where possible. The memory used is
+// about (1.04/stdError)^2 bytes, between 16 bytes (for a standard error of
+// 26%) and 256KiB (0.2%). The hasher is used to hash elements; if it is nil,
+// the default Hasher for T is used (see Hasher).
+// It panics if stdError is not strictly between 0 and 1, or if hasher is nil
+// and T has no default Hasher.
+func NewHyperLogLog[T any](stdError float64, hasher Hasher[T]) *HyperLogLog[T] {
+	if !(stdError > 0 && stdError < 1) {
+		panic(fmt.Sprintf("standard error %v not between 0 and 1", stdError))
+	}
+	p := int(math.Ceil(math.Log2(math.Pow(1.04/stdError, 2))))
+	p = max(minHLLPrecision, min(p, maxHLLPrecision))
+	return &HyperLogLog[T]{
+		registers: make([]uint8, 1<<p),
+		p:         uint8(p),
+		hasher:    hasherOrDefault(hasher),
+	}
+}
+
+// Basic (non-mutating) functions
+
+// Count returns an estimate of the number of distinct elements added to this
+// HyperLogLog.
+func (h *HyperLogLog[T]) Count() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+
+	// For small cardinalities, linear counting is more accurate.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+// StandardError returns the relative standard error of this HyperLogLog's
+// estimates.
+func (h *HyperLogLog[T]) StandardError() float64 {
+	return 1.04 / math.Sqrt(float64(len(h.registers)))
+}
+
+// Basic (mutating) functions
+
+// Add adds t to this HyperLogLog.
+func (h *HyperLogLog[T]) Add(t T) {
+	x := h.hasher(t)
+	// The first p bits choose a register; the rest give the rank, i.e. the
+	// position of the first 1 bit. The extra 1 bit caps the rank at 64-p+1.
+	i := x >> (64 - h.p)
+	w := x<<h.p | 1<<(h.p-1)
+	rank := uint8(bits.LeadingZeros64(w) + 1)
+	if rank > h.registers[i] {
+		h.registers[i] = rank
+	}
+}
+
+// Merge modifies this HyperLogLog so that it estimates the number of
+// distinct elements added to it or to other. Both HyperLogLogs must use the
+// same hasher; Merge returns an error if they have different precisions.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if other.p != h.p {
+		return fmt.Errorf("incompatible HyperLogLogs: precision %d vs %d", h.p, other.p)
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// Clear resets this HyperLogLog to its initial state.
+func (h *HyperLogLog[T]) Clear() {
+	clear(h.registers)
+}
+
+// Copying functions
+
+// Copy returns a copy of the given HyperLogLog.
+func (h *HyperLogLog[T]) Copy() *HyperLogLog[T] {
+	registers := make([]uint8, len(h.registers))
+	copy(registers, h.registers)
+	return &HyperLogLog[T]{registers, h.p, h.hasher}
+}
+
+// Internal functions
+
+// hllAlpha returns the bias correction constant for m registers.
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
@@ -0,0 +1,90 @@
+package collections
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// hllErrorBound is the number of standard errors within which every
+// estimate must lie. Estimates are roughly normally distributed, so a single
+// estimate falls outside 4 standard errors with probability below 0.01%.
+const hllErrorBound = 4
+
+func TestHyperLogLogErrorBounds(t *testing.T) {
+	for _, stdError := range []float64{0.1, 0.02} {
+		for _, n := range []int{1, 10, 100, 1000, 10000, 100000, 1000000} {
+			r := rand.New(seeded(uint64(n)))
+			h := NewHyperLogLog[uint64](stdError, nil)
+			for i := 0; i < n; i++ {
+				x := r.Uint64()
+				h.Add(x)
+				h.Add(x) // duplicates must not be counted
+			}
+
+			relErr := math.Abs(float64(h.Count())-float64(n)) / float64(n)
+			if relErr > hllErrorBound*h.StandardError() {
+				t.Errorf("stdError=%v, n=%d: estimate %d has relative error %.4f, want at most %.4f",
+					stdError, n, h.Count(), relErr, hllErrorBound*h.StandardError())
+			}
+		}
+	}
+}
+
+func TestHyperLogLogStandardError(t *testing.T) {
+	// Over many seeded runs, the root-mean-square relative error should be
+	// close to StandardError().
+	const n, runs = 20000, 40
+	sumSq := 0.0
+	var se float64
+	for run := 0; run < runs; run++ {
+		r := rand.New(seeded(uint64(run)))
+		h := NewHyperLogLog[uint64](0.05, nil)
+		se = h.StandardError()
+		if se > 0.05 {
+			t.Fatalf("StandardError() = %v, want at most 0.05", se)
+		}
+		for i := 0; i < n; i++ {
+			h.Add(r.Uint64())
+		}
+		relErr := (float64(h.Count()) - n) / n
+		sumSq += relErr * relErr
+	}
+	if rms := math.Sqrt(sumSq / runs); rms > 1.5*se {
+		t.Errorf("RMS relative error %.4f, want about %.4f", rms, se)
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	h1 := NewHyperLogLog[int](0.02, nil)
+	h2 := NewHyperLogLog[int](0.02, nil)
+	combined := NewHyperLogLog[int](0.02, nil)
+	for i := 0; i < 50000; i++ {
+		h1.Add(i)
+		combined.Add(i)
+	}
+	for i := 30000; i < 90000; i++ {
+		h2.Add(i)
+		combined.Add(i)
+	}
+
+	merged := h1.Copy()
+	if err := merged.Merge(h2); err != nil {
+		t.Fatal(err)
+	}
+	if merged.Count() != combined.Count() {
+		t.Errorf("merged count %d differs from combined count %d", merged.Count(), combined.Count())
+	}
+	if h1.Count() == merged.Count() {
+		t.Errorf("Merge appears to have modified the original via Copy")
+	}
+
+	if err := h1.Merge(NewHyperLogLog[int](0.1, nil)); err == nil {
+		t.Errorf("expected error merging HyperLogLogs of different precision")
+	}
+
+	h1.Clear()
+	if h1.Count() != 0 {
+		t.Errorf("Count() = %d after Clear", h1.Count())
+	}
+}
@@ -0,0 +1,299 @@
+package collections
+
+import "fmt"
+
+// Interval is a half-open interval [Low, High) of keys, containing all keys k
+// with Low <= k < High.
+type Interval[K any] struct {
+	Low, High K
+}
+
+// IntervalTree is a collection of intervals, each associated with a value,
+// which supports efficient queries for the intervals overlapping a given
+// point or interval. The same interval may be inserted several times.
+//
+// Keys are ordered by the less function given to NewIntervalTree, in the same
+// way as Map.Iterate orders keys.
+type IntervalTree[K, V any] struct {
+	root *intervalNode[K, V]
+	size int
+	less func(K, K) bool
+}
+
+// intervalNode is a node in an AVL tree ordered by interval.
+type intervalNode[K, V any] struct {
+	iv          Interval[K]
+	value       V
+	left, right *intervalNode[K, V]
+	height      int
+	// maxHigh is the largest High of any interval in this subtree.
+	maxHigh K
+}
+
+// Constructors
+
+// NewIntervalTree makes a new empty IntervalTree, using less to order keys.
+func NewIntervalTree[K, V any](less func(K, K) bool) *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{less: less}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of intervals in this IntervalTree.
+func (t *IntervalTree[K, V]) Size() int {
+	return t.size
+}
+
+// IsEmpty returns true if this IntervalTree is empty.
+func (t *IntervalTree[K, V]) IsEmpty() bool {
+	return t.Size() == 0
+}
+
+// Basic (mutating) functions
+
+// Insert adds the interval iv with associated value v to this IntervalTree.
+// It returns an error if iv is empty, i.e. iv.Low is not less than iv.High.
+func (t *IntervalTree[K, V]) Insert(iv Interval[K], v V) error {
+	if !t.less(iv.Low, iv.High) {
+		return errEmptyInterval(iv)
+	}
+	t.root = t.insert(t.root, &intervalNode[K, V]{iv: iv, value: v, height: 1, maxHigh: iv.High})
+	t.size++
+	return nil
+}
+
+// Remove removes one occurrence of the interval iv from this IntervalTree.
+// It returns false if iv was not in the IntervalTree to begin with, and
+// returns true if iv was removed.
+func (t *IntervalTree[K, V]) Remove(iv Interval[K]) bool {
+	var removed bool
+	t.root, removed = t.remove(t.root, iv)
+	if removed {
+		t.size--
+	}
+	return removed
+}
+
+// Clear removes all intervals from this IntervalTree.
+func (t *IntervalTree[K, V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
+
+// Iteration
+
+// intervalIterator performs an in-order traversal of the tree, skipping
+// subtrees which cannot overlap the query interval.
+type intervalIterator[K, V any] struct {
+	stack *Stack[*intervalNode[K, V]]
+	// overlaps returns true if the interval should be returned.
+	overlaps func(Interval[K]) bool
+	// mayOverlapLow (resp. mayOverlapHigh) returns false if no interval with
+	// the given Low (resp. High) or greater could be returned.
+	mayOverlapLow  func(K) bool
+	mayOverlapHigh func(K) bool
+	next           *intervalNode[K, V]
+}
+
+func (i *intervalIterator[K, V]) pushLeft(n *intervalNode[K, V]) {
+	for n != nil && i.mayOverlapHigh(n.maxHigh) {
+		i.stack.Push(n)
+		n = n.left
+	}
+}
+
+func (i *intervalIterator[K, V]) advance() {
+	i.next = nil
+	for !i.stack.IsEmpty() {
+		n, _ := i.stack.Pop()
+		// intervals in the right subtree all start at or after n.iv.Low
+		if i.mayOverlapLow(n.iv.Low) {
+			i.pushLeft(n.right)
+		}
+		if i.overlaps(n.iv) {
+			i.next = n
+			return
+		}
+	}
+}
+
+func (i *intervalIterator[K, V]) HasNext() bool {
+	return i.next != nil
+}
+
+func (i *intervalIterator[K, V]) Next() (Interval[K], V) {
+	n := i.next
+	i.advance()
+	return n.iv, n.value
+}
+
+func (t *IntervalTree[K, V]) iterate(overlaps func(Interval[K]) bool, mayOverlapLow, mayOverlapHigh func(K) bool) Iterator2[Interval[K], V] {
+	it := &intervalIterator[K, V]{
+		stack:          NewStack[*intervalNode[K, V]](t.root.getHeight()),
+		overlaps:       overlaps,
+		mayOverlapLow:  mayOverlapLow,
+		mayOverlapHigh: mayOverlapHigh,
+	}
+	it.pushLeft(t.root)
+	it.advance()
+	return it
+}
+
+// Iterate returns an Iterator2 over all intervals in this IntervalTree and
+// their values, ordered by Low and then by High. The IntervalTree must not be
+// modified during iteration.
+func (t *IntervalTree[K, V]) Iterate() Iterator2[Interval[K], V] {
+	always := func(K) bool { return true }
+	return t.iterate(func(Interval[K]) bool { return true }, always, always)
+}
+
+// Overlapping returns an Iterator2 over the intervals in this IntervalTree
+// which overlap iv (i.e. have at least one key in common with iv), and their
+// values, ordered by Low and then by High. The IntervalTree must not be
+// modified during iteration.
+func (t *IntervalTree[K, V]) Overlapping(iv Interval[K]) Iterator2[Interval[K], V] {
+	return t.iterate(
+		func(x Interval[K]) bool { return t.less(x.Low, iv.High) && t.less(iv.Low, x.High) },
+		func(low K) bool { return t.less(low, iv.High) },
+		func(high K) bool { return t.less(iv.Low, high) },
+	)
+}
+
+// OverlappingPoint returns an Iterator2 over the intervals in this
+// IntervalTree which contain the key k, and their values, ordered by Low and
+// then by High. The IntervalTree must not be modified during iteration.
+func (t *IntervalTree[K, V]) OverlappingPoint(k K) Iterator2[Interval[K], V] {
+	return t.iterate(
+		func(x Interval[K]) bool { return !t.less(k, x.Low) && t.less(k, x.High) },
+		func(low K) bool { return !t.less(k, low) },
+		func(high K) bool { return t.less(k, high) },
+	)
+}
+
+// Internal methods
+
+// compare orders intervals by Low, then by High.
+func (t *IntervalTree[K, V]) compare(a, b Interval[K]) int {
+	switch {
+	case t.less(a.Low, b.Low):
+		return -1
+	case t.less(b.Low, a.Low):
+		return 1
+	case t.less(a.High, b.High):
+		return -1
+	case t.less(b.High, a.High):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (t *IntervalTree[K, V]) insert(n, new *intervalNode[K, V]) *intervalNode[K, V] {
+	if n == nil {
+		return new
+	}
+	if t.compare(new.iv, n.iv) < 0 {
+		n.left = t.insert(n.left, new)
+	} else {
+		n.right = t.insert(n.right, new)
+	}
+	return t.rebalance(n)
+}
+
+func (t *IntervalTree[K, V]) remove(n *intervalNode[K, V], iv Interval[K]) (*intervalNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	switch c := t.compare(iv, n.iv); {
+	case c < 0:
+		n.left, removed = t.remove(n.left, iv)
+	case c > 0:
+		n.right, removed = t.remove(n.right, iv)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// replace n with its successor
+		var succ *intervalNode[K, V]
+		n.right, succ = t.removeMin(n.right)
+		succ.left, succ.right = n.left, n.right
+		return t.rebalance(succ), true
+	}
+	return t.rebalance(n), removed
+}
+
+// removeMin removes the smallest node in the subtree rooted at n, returning
+// the new subtree root and the removed node.
+func (t *IntervalTree[K, V]) removeMin(n *intervalNode[K, V]) (*intervalNode[K, V], *intervalNode[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	var min *intervalNode[K, V]
+	n.left, min = t.removeMin(n.left)
+	return t.rebalance(n), min
+}
+
+func (n *intervalNode[K, V]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recomputes the height and maxHigh of n from its children.
+func (t *IntervalTree[K, V]) update(n *intervalNode[K, V]) {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+	n.maxHigh = n.iv.High
+	for _, c := range []*intervalNode[K, V]{n.left, n.right} {
+		if c != nil && t.less(n.maxHigh, c.maxHigh) {
+			n.maxHigh = c.maxHigh
+		}
+	}
+}
+
+func (t *IntervalTree[K, V]) rotateLeft(n *intervalNode[K, V]) *intervalNode[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	t.update(n)
+	t.update(r)
+	return r
+}
+
+func (t *IntervalTree[K, V]) rotateRight(n *intervalNode[K, V]) *intervalNode[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	t.update(n)
+	t.update(l)
+	return l
+}
+
+// rebalance restores the AVL balance property at n, assuming its subtrees
+// are balanced, and returns the new subtree root.
+func (t *IntervalTree[K, V]) rebalance(n *intervalNode[K, V]) *intervalNode[K, V] {
+	t.update(n)
+	switch balance := n.left.getHeight() - n.right.getHeight(); {
+	case balance > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = t.rotateLeft(n.left)
+		}
+		return t.rotateRight(n)
+	case balance < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = t.rotateRight(n.right)
+		}
+		return t.rotateLeft(n)
+	}
+	return n
+}
+
+// Errors
+
+func errEmptyInterval(iv any) error {
+	return fmt.Errorf("interval %v is empty", iv)
+}
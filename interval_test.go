@@ -0,0 +1,173 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkIntervalNode checks the AVL and maxHigh invariants of the subtree
+// rooted at n, returning its height.
+func checkIntervalNode[V any](t *testing.T, tree *IntervalTree[int, V], n *intervalNode[int, V]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	lh := checkIntervalNode(t, tree, n.left)
+	rh := checkIntervalNode(t, tree, n.right)
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("node %v is unbalanced: left height %d, right height %d", n.iv, lh, rh)
+	}
+	if n.height != 1+max(lh, rh) {
+		t.Fatalf("node %v has height %d, want %d", n.iv, n.height, 1+max(lh, rh))
+	}
+	maxHigh := n.iv.High
+	if n.left != nil {
+		if tree.compare(n.left.iv, n.iv) > 0 {
+			t.Fatalf("left child %v of %v is out of order", n.left.iv, n.iv)
+		}
+		maxHigh = max(maxHigh, n.left.maxHigh)
+	}
+	if n.right != nil {
+		if tree.compare(n.right.iv, n.iv) < 0 {
+			t.Fatalf("right child %v of %v is out of order", n.right.iv, n.iv)
+		}
+		maxHigh = max(maxHigh, n.right.maxHigh)
+	}
+	if n.maxHigh != maxHigh {
+		t.Fatalf("node %v has maxHigh %d, want %d", n.iv, n.maxHigh, maxHigh)
+	}
+	return n.height
+}
+
+func compareIntervals(a, b Interval[int]) int {
+	if a.Low != b.Low {
+		return a.Low - b.Low
+	}
+	return a.High - b.High
+}
+
+// collectIntervals returns the intervals given by it.
+func collectIntervals[V any](it Iterator2[Interval[int], V]) []Interval[int] {
+	var ivs []Interval[int]
+	for it.HasNext() {
+		iv, _ := it.Next()
+		ivs = append(ivs, iv)
+	}
+	return ivs
+}
+
+func randomInterval(r *rand.Rand) Interval[int] {
+	low := r.IntN(100)
+	return Interval[int]{low, low + 1 + r.IntN(20)}
+}
+
+func TestIntervalTreeAgainstBruteForce(t *testing.T) {
+	r := rand.New(seeded(38))
+	tree := NewIntervalTree[int, int](intLess)
+	var ref []Interval[int] // kept sorted
+
+	for i := 0; i < 3000; i++ {
+		iv := randomInterval(r)
+		if r.IntN(3) == 0 && len(ref) > 0 {
+			// remove an existing interval, or possibly a missing one
+			if r.IntN(2) == 0 {
+				iv = ref[r.IntN(len(ref))]
+			}
+			j, found := slices.BinarySearchFunc(ref, iv, compareIntervals)
+			if found {
+				ref = slices.Delete(ref, j, j+1)
+			}
+			if got := tree.Remove(iv); got != found {
+				t.Fatalf("Remove(%v) = %v, want %v", iv, got, found)
+			}
+		} else {
+			if err := tree.Insert(iv, i); err != nil {
+				t.Fatal(err)
+			}
+			j, _ := slices.BinarySearchFunc(ref, iv, compareIntervals)
+			ref = slices.Insert(ref, j, iv)
+		}
+
+		checkIntervalNode(t, tree, tree.root)
+		if tree.Size() != len(ref) {
+			t.Fatalf("Size() = %d, want %d", tree.Size(), len(ref))
+		}
+		if got := collectIntervals(tree.Iterate()); !slices.Equal(got, ref) {
+			t.Fatalf("Iterate() = %v, want %v", got, ref)
+		}
+
+		query := randomInterval(r)
+		var want []Interval[int]
+		for _, x := range ref {
+			if x.Low < query.High && query.Low < x.High {
+				want = append(want, x)
+			}
+		}
+		if got := collectIntervals(tree.Overlapping(query)); !slices.Equal(got, want) {
+			t.Fatalf("Overlapping(%v) = %v, want %v", query, got, want)
+		}
+
+		k := r.IntN(130)
+		want = want[:0]
+		for _, x := range ref {
+			if x.Low <= k && k < x.High {
+				want = append(want, x)
+			}
+		}
+		if got := collectIntervals(tree.OverlappingPoint(k)); !slices.Equal(got, want) {
+			t.Fatalf("OverlappingPoint(%d) = %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestIntervalTreeBalancedSequentialInsert(t *testing.T) {
+	// Inserting in sorted order degenerates an unbalanced tree into a list.
+	tree := NewIntervalTree[int, int](intLess)
+	const n = 1 << 12
+	for i := 0; i < n; i++ {
+		tree.Insert(Interval[int]{i, i + 1}, i)
+	}
+	height := checkIntervalNode(t, tree, tree.root)
+	if float64(height) > 1.45*13 { // AVL height is below 1.45 log2(n+2)
+		t.Errorf("height %d for %d nodes exceeds the AVL bound", height, n)
+	}
+	for i := 0; i < n; i += 2 {
+		tree.Remove(Interval[int]{i, i + 1})
+	}
+	checkIntervalNode(t, tree, tree.root)
+	if tree.Size() != n/2 {
+		t.Errorf("Size() = %d, want %d", tree.Size(), n/2)
+	}
+}
+
+func TestIntervalTreeValues(t *testing.T) {
+	tree := NewIntervalTree[int, string](intLess)
+	tree.Insert(Interval[int]{0, 10}, "a")
+	tree.Insert(Interval[int]{5, 15}, "b")
+	tree.Insert(Interval[int]{0, 10}, "c")
+	if err := tree.Insert(Interval[int]{3, 3}, "empty"); err == nil {
+		t.Errorf("expected error inserting an empty interval")
+	}
+
+	var values []string
+	for it := tree.OverlappingPoint(10); it.HasNext(); {
+		_, v := it.Next()
+		values = append(values, v)
+	}
+	if !slices.Equal(values, []string{"b"}) {
+		t.Errorf("OverlappingPoint(10) = %q, want [b] (intervals are half-open)", values)
+	}
+
+	if !tree.Remove(Interval[int]{0, 10}) || tree.Size() != 2 {
+		t.Errorf("Remove should remove one occurrence of a repeated interval")
+	}
+	if got := collectIntervals(tree.OverlappingPoint(0)); len(got) != 1 {
+		t.Errorf("OverlappingPoint(0) = %v after removing one occurrence", got)
+	}
+
+	tree.Clear()
+	if !tree.IsEmpty() || tree.Iterate().HasNext() {
+		t.Errorf("tree not empty after Clear")
+	}
+}
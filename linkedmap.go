@@ -0,0 +1,247 @@
+package collections
+
+import "fmt"
+
+// LinkedMap is an implementation of a map which remembers the order in which
+// keys were inserted, using a Go hashmap and a doubly linked list.
+//
+// By default, iteration over a LinkedMap is in insertion order: re-setting an
+// existing key does not change its position. A LinkedMap created with
+// NewAccessOrderLinkedMap instead iterates in access order, from least to
+// most recently accessed, where Get and Set count as accesses.
+type LinkedMap[K comparable, V any] struct {
+	entries map[K]*linkedEntry[K, V]
+	// root is a sentinel: root.next is the first entry and root.prev is the
+	// last entry.
+	root        linkedEntry[K, V]
+	accessOrder bool
+}
+
+// linkedEntry is an entry in a LinkedMap.
+type linkedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *linkedEntry[K, V]
+}
+
+// Constructors
+
+// NewLinkedMap makes a new insertion-ordered LinkedMap with the specified
+// initial capacity.
+func NewLinkedMap[K comparable, V any](capacity int) *LinkedMap[K, V] {
+	m := &LinkedMap[K, V]{entries: make(map[K]*linkedEntry[K, V], capacity)}
+	m.root.prev = &m.root
+	m.root.next = &m.root
+	return m
+}
+
+// NewAccessOrderLinkedMap makes a new access-ordered LinkedMap with the
+// specified initial capacity.
+func NewAccessOrderLinkedMap[K comparable, V any](capacity int) *LinkedMap[K, V] {
+	m := NewLinkedMap[K, V](capacity)
+	m.accessOrder = true
+	return m
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of entries in this LinkedMap.
+func (m *LinkedMap[K, V]) Size() int {
+	return len(m.entries)
+}
+
+// IsEmpty returns true if this LinkedMap is empty.
+func (m *LinkedMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Contains returns true if the given key is in the LinkedMap. Contains does
+// not count as an access.
+func (m *LinkedMap[K, V]) Contains(k K) bool {
+	_, ok := m.entries[k]
+	return ok
+}
+
+// Get returns the value associated with k. If k is not a key in this
+// LinkedMap, Get returns an error.
+func (m *LinkedMap[K, V]) Get(k K) (v V, err error) {
+	e, ok := m.entries[k]
+	if !ok {
+		err = errKeyNotFound(k)
+		return
+	}
+	m.access(e)
+	v = e.value
+	return
+}
+
+// GetOrDefault returns the value associated with k, or def if k is not a key
+// in this LinkedMap.
+func (m *LinkedMap[K, V]) GetOrDefault(k K, def V) V {
+	v, err := m.Get(k)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// First returns the first entry of this LinkedMap, i.e. the least recently
+// inserted (or accessed) entry. It returns an error if the LinkedMap is
+// empty. First does not count as an access.
+func (m *LinkedMap[K, V]) First() (k K, v V, err error) {
+	if m.IsEmpty() {
+		err = errLinkedMapEmpty
+		return
+	}
+	return m.root.next.key, m.root.next.value, nil
+}
+
+// Last returns the last entry of this LinkedMap, i.e. the most recently
+// inserted (or accessed) entry. It returns an error if the LinkedMap is
+// empty. Last does not count as an access.
+func (m *LinkedMap[K, V]) Last() (k K, v V, err error) {
+	if m.IsEmpty() {
+		err = errLinkedMapEmpty
+		return
+	}
+	return m.root.prev.key, m.root.prev.value, nil
+}
+
+// Keys returns all keys present in this LinkedMap, in order.
+func (m *LinkedMap[K, V]) Keys() *List[K] {
+	keys := NewList[K](m.Size())
+	for e := m.root.next; e != &m.root; e = e.next {
+		keys.Append(e.key)
+	}
+	return keys
+}
+
+// Values returns all values present in this LinkedMap, in order.
+func (m *LinkedMap[K, V]) Values() *List[V] {
+	values := NewList[V](m.Size())
+	for e := m.root.next; e != &m.root; e = e.next {
+		values.Append(e.value)
+	}
+	return values
+}
+
+// Entries returns all key-value pairs present in this LinkedMap, in order.
+func (m *LinkedMap[K, V]) Entries() *List[Entry[K, V]] {
+	entries := NewList[Entry[K, V]](m.Size())
+	for e := m.root.next; e != &m.root; e = e.next {
+		entries.Append(Entry[K, V]{e.key, e.value})
+	}
+	return entries
+}
+
+// Basic (mutating) functions
+
+// Set adds the given key-value pair to this LinkedMap. If there is already a
+// value associated with k, it will be overwritten.
+// A new key is added to the end of the LinkedMap.
+func (m *LinkedMap[K, V]) Set(k K, v V) {
+	if e, ok := m.entries[k]; ok {
+		e.value = v
+		m.access(e)
+		return
+	}
+
+	e := &linkedEntry[K, V]{key: k, value: v}
+	m.entries[k] = e
+	m.insertLast(e)
+}
+
+// Remove removes k and its associated value from this LinkedMap. It returns
+// false if k was not in the LinkedMap to begin with, and returns true if k was
+// removed.
+func (m *LinkedMap[K, V]) Remove(k K) bool {
+	e, ok := m.entries[k]
+	if !ok {
+		return false
+	}
+	delete(m.entries, k)
+	m.unlink(e)
+	return true
+}
+
+// Clear removes all entries from this LinkedMap.
+func (m *LinkedMap[K, V]) Clear() {
+	clear(m.entries)
+	m.root.prev = &m.root
+	m.root.next = &m.root
+}
+
+// Copying functions
+
+// Copy returns a copy of the given LinkedMap, with the same order and
+// ordering mode.
+func (m *LinkedMap[K, V]) Copy() *LinkedMap[K, V] {
+	cp := NewLinkedMap[K, V](m.Size())
+	cp.accessOrder = m.accessOrder
+	for e := m.root.next; e != &m.root; e = e.next {
+		cp.Set(e.key, e.value)
+	}
+	return cp
+}
+
+// Iteration
+
+// entryIterator iterates over a List of entries.
+type entryIterator[K, V any] struct {
+	entries *List[Entry[K, V]]
+	index   int
+}
+
+func (i *entryIterator[K, V]) HasNext() bool {
+	return i.index < i.entries.Size()
+}
+
+func (i *entryIterator[K, V]) Next() (K, V) {
+	e := (*i.entries)[i.index]
+	i.index++
+	return e.Key, e.Value
+}
+
+// Iterate returns an Iterator2 iterating over the given LinkedMap.
+// If a non-nil comparator keyOrder is provided, then the iteration will be in
+// the order determined on the keys. Otherwise, the iteration is in the order
+// of the LinkedMap. Iteration does not count as an access.
+func (m *LinkedMap[K, V]) Iterate(keyOrder func(K, K) bool) Iterator2[K, V] {
+	entries := m.Entries()
+	if keyOrder != nil {
+		entries.Sort(func(e, f Entry[K, V]) bool {
+			return keyOrder(e.Key, f.Key)
+		})
+	}
+	return &entryIterator[K, V]{
+		entries: entries,
+		index:   0,
+	}
+}
+
+// Internal methods
+
+// access moves e to the end of the list, if this LinkedMap is access-ordered.
+func (m *LinkedMap[K, V]) access(e *linkedEntry[K, V]) {
+	if m.accessOrder && e != m.root.prev {
+		m.unlink(e)
+		m.insertLast(e)
+	}
+}
+
+func (m *LinkedMap[K, V]) insertLast(e *linkedEntry[K, V]) {
+	e.prev = m.root.prev
+	e.next = &m.root
+	m.root.prev.next = e
+	m.root.prev = e
+}
+
+func (m *LinkedMap[K, V]) unlink(e *linkedEntry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+}
+
+// Errors
+var errLinkedMapEmpty = fmt.Errorf("linked map is empty")
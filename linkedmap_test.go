@@ -0,0 +1,129 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func linkedMapKeys[K comparable, V any](m *LinkedMap[K, V]) []K {
+	var keys []K
+	for it := m.Iterate(nil); it.HasNext(); {
+		k, _ := it.Next()
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestLinkedMapInsertionOrder(t *testing.T) {
+	m := NewLinkedMap[string, int](0)
+	for i, k := range []string{"c", "a", "d", "b"} {
+		m.Set(k, i)
+	}
+	m.Set("a", 10) // re-setting keeps the position
+	m.Get("c")     // access does not reorder
+
+	if got := linkedMapKeys(m); !slices.Equal(got, []string{"c", "a", "d", "b"}) {
+		t.Errorf("keys = %v, want insertion order", got)
+	}
+	if got := *m.Values(); !slices.Equal(got, []int{0, 10, 2, 3}) {
+		t.Errorf("values = %v", got)
+	}
+
+	m.Remove("d")
+	m.Set("d", 4)
+	if got := *m.Keys(); !slices.Equal(got, []string{"c", "a", "b", "d"}) {
+		t.Errorf("keys = %v after re-adding d, want it last", got)
+	}
+	if k, v, _ := m.First(); k != "c" || v != 0 {
+		t.Errorf("First() = %s, %d", k, v)
+	}
+	if k, v, _ := m.Last(); k != "d" || v != 4 {
+		t.Errorf("Last() = %s, %d", k, v)
+	}
+}
+
+func TestLinkedMapAccessOrder(t *testing.T) {
+	m := NewAccessOrderLinkedMap[int, string](0)
+	for i := 0; i < 4; i++ {
+		m.Set(i, "")
+	}
+	m.Get(1)
+	m.Set(0, "updated")
+	m.GetOrDefault(2, "")
+
+	if got := linkedMapKeys(m); !slices.Equal(got, []int{3, 1, 0, 2}) {
+		t.Errorf("keys = %v, want least to most recently accessed", got)
+	}
+
+	cp := m.Copy()
+	cp.Get(3)
+	if got := linkedMapKeys(cp); !slices.Equal(got, []int{1, 0, 2, 3}) {
+		t.Errorf("copy keys = %v, want access order preserved", got)
+	}
+	if got := linkedMapKeys(m); !slices.Equal(got, []int{3, 1, 0, 2}) {
+		t.Errorf("original keys = %v changed by accessing copy", got)
+	}
+}
+
+func TestLinkedMapAgainstMap(t *testing.T) {
+	r := rand.New(seeded(1))
+	m := NewLinkedMap[int, int](0)
+	ref := map[int]int{}
+	var order []int
+
+	for i := 0; i < 5000; i++ {
+		k := r.IntN(50)
+		switch r.IntN(3) {
+		case 0, 1:
+			if _, ok := ref[k]; !ok {
+				order = append(order, k)
+			}
+			ref[k] = i
+			m.Set(k, i)
+		case 2:
+			_, ok := ref[k]
+			if m.Remove(k) != ok {
+				t.Fatalf("Remove(%d) disagrees with map", k)
+			}
+			delete(ref, k)
+			order = slices.DeleteFunc(order, func(x int) bool { return x == k })
+		}
+
+		if m.Size() != len(ref) {
+			t.Fatalf("Size() = %d, want %d", m.Size(), len(ref))
+		}
+		if v, err := m.Get(k); (err == nil) != m.Contains(k) || (err == nil && v != ref[k]) {
+			t.Fatalf("Get(%d) = %d, %v; want %d", k, v, err, ref[k])
+		}
+	}
+	if got := linkedMapKeys(m); !slices.Equal(got, order) {
+		t.Errorf("keys = %v, want %v", got, order)
+	}
+
+	m.Clear()
+	if !m.IsEmpty() || m.Iterate(nil).HasNext() {
+		t.Errorf("LinkedMap not empty after Clear")
+	}
+	if _, _, err := m.First(); err == nil {
+		t.Errorf("expected error from First on empty LinkedMap")
+	}
+}
+
+func TestLinkedMapIterateSorted(t *testing.T) {
+	m := NewLinkedMap[int, int](0)
+	for _, k := range []int{3, 1, 2} {
+		m.Set(k, k*k)
+	}
+	var got []int
+	for it := m.Iterate(intLess); it.HasNext(); {
+		k, v := it.Next()
+		if v != k*k {
+			t.Errorf("key %d has value %d", k, v)
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("sorted iteration = %v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package collections
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// LinkedSet is an implementation of a set which remembers the order in which
+// elements were inserted. Iterating over a LinkedSet, or converting it to a
+// slice or string, always gives the elements in insertion order.
+type LinkedSet[T comparable] struct {
+	m *LinkedMap[T, o]
+}
+
+// String returns a string representation of this LinkedSet.
+func (s *LinkedSet[T]) String() string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, t := range s.Slice() {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%v", t)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// Constructors
+
+// NewLinkedSet makes a new LinkedSet with the specified initial capacity.
+func NewLinkedSet[T comparable](capacity int) *LinkedSet[T] {
+	return &LinkedSet[T]{NewLinkedMap[T, o](capacity)}
+}
+
+// AsLinkedSet returns a LinkedSet containing the elements of the given slice,
+// in the order they first appear.
+func AsLinkedSet[T comparable](elems []T) *LinkedSet[T] {
+	s := NewLinkedSet[T](len(elems))
+	for _, t := range elems {
+		s.Add(t)
+	}
+	return s
+}
+
+// Slice returns a slice containing the elements of this LinkedSet, in
+// insertion order.
+func (s *LinkedSet[T]) Slice() []T {
+	return s.m.Keys().AsSlice()
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of elements in this LinkedSet.
+func (s *LinkedSet[T]) Size() int {
+	return s.m.Size()
+}
+
+// IsEmpty returns true if this LinkedSet is empty.
+func (s *LinkedSet[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Contains returns true if the given element is in the LinkedSet.
+func (s *LinkedSet[T]) Contains(t T) bool {
+	return s.m.Contains(t)
+}
+
+// RandomElement returns an element of this LinkedSet chosen uniformly at
+// random using the given source. If src is nil, the global random source is
+// used. It returns an error if the LinkedSet is empty.
+//
+// Unlike Set.RandomElement, the result is reproducible for a given source.
+func (s *LinkedSet[T]) RandomElement(src rand.Source) (t T, err error) {
+	if s.IsEmpty() {
+		err = errSetEmpty
+		return
+	}
+	return s.m.Keys().RandomElement(src)
+}
+
+// Basic (mutating) functions
+
+// Add adds t to the end of this LinkedSet, if it is not already in the
+// LinkedSet. If t is already in the LinkedSet, its position is unchanged.
+func (s *LinkedSet[T]) Add(t T) {
+	if !s.m.Contains(t) {
+		s.m.Set(t, o{})
+	}
+}
+
+// Remove removes t from this LinkedSet. It returns false if t was not in the
+// LinkedSet to begin with, and returns true if t was removed from the
+// LinkedSet.
+func (s *LinkedSet[T]) Remove(t T) bool {
+	return s.m.Remove(t)
+}
+
+// Pop removes an element chosen uniformly at random from this LinkedSet, and
+// returns it. If src is nil, the global random source is used. It returns an
+// error if the LinkedSet is empty.
+func (s *LinkedSet[T]) Pop(src rand.Source) (t T, err error) {
+	t, err = s.RandomElement(src)
+	if err == nil {
+		s.Remove(t)
+	}
+	return
+}
+
+// Copying functions
+
+// Copy returns a copy of the given LinkedSet, with the same order.
+func (s *LinkedSet[T]) Copy() *LinkedSet[T] {
+	return &LinkedSet[T]{s.m.Copy()}
+}
+
+// Iteration
+
+// Iterate returns an Iterator iterating over the given LinkedSet in
+// insertion order.
+func (s *LinkedSet[T]) Iterate() Iterator[T] {
+	return &listIterator[T]{
+		l:     s.m.Keys(),
+		index: 0,
+	}
+}
+
+// LinkedUnion returns the LinkedSet of all elements which are in any of the
+// given LinkedSets. Elements are ordered by their first appearance in the
+// given LinkedSets.
+func LinkedUnion[T comparable](sets ...*LinkedSet[T]) *LinkedSet[T] {
+	union := NewLinkedSet[T](0)
+	for _, set := range sets {
+		for _, t := range set.Slice() {
+			union.Add(t)
+		}
+	}
+	return union
+}
+
+// LinkedIntersection returns the LinkedSet of elements which are in all of
+// the given LinkedSets, in the order they appear in the first LinkedSet.
+func LinkedIntersection[T comparable](sets ...*LinkedSet[T]) *LinkedSet[T] {
+	if len(sets) == 0 {
+		return nil
+	}
+	intsec := NewLinkedSet[T](sets[0].Size())
+elementLoop:
+	for _, t := range sets[0].Slice() {
+		for _, set := range sets[1:] {
+			if !set.Contains(t) {
+				continue elementLoop
+			}
+		}
+		intsec.Add(t)
+	}
+	return intsec
+}
+
+// LinkedDifference returns the LinkedSet of all elements in s1 which are not
+// in s2, in the order they appear in s1.
+func LinkedDifference[T comparable](s1, s2 *LinkedSet[T]) *LinkedSet[T] {
+	diff := NewLinkedSet[T](s1.Size())
+	for _, t := range s1.Slice() {
+		if !s2.Contains(t) {
+			diff.Add(t)
+		}
+	}
+	return diff
+}
+
+// LinkedSymmetricDifference returns the LinkedSet of all elements which are
+// in exactly one of s1 and s2. Elements of s1 come first, followed by
+// elements of s2.
+func LinkedSymmetricDifference[T comparable](s1, s2 *LinkedSet[T]) *LinkedSet[T] {
+	return LinkedUnion(LinkedDifference(s1, s2), LinkedDifference(s2, s1))
+}
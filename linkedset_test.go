@@ -0,0 +1,50 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinkedSet(t *testing.T) {
+	s := AsLinkedSet([]int{3, 1, 3, 2, 1})
+	if got := s.Slice(); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Slice() = %v, want first-appearance order", got)
+	}
+	if got := s.String(); got != "{3, 1, 2}" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := NewLinkedSet[int](0).String(); got != "{}" {
+		t.Errorf("empty String() = %q", got)
+	}
+
+	a, b := seeded(4), seeded(4)
+	x, _ := s.RandomElement(a)
+	y, _ := s.Copy().RandomElement(b)
+	if x != y {
+		t.Errorf("RandomElement not reproducible: %d, %d", x, y)
+	}
+	if !s.Remove(1) || s.Remove(1) || s.Contains(1) {
+		t.Errorf("Remove(1) did not behave as expected")
+	}
+}
+
+func TestLinkedSetAlgebra(t *testing.T) {
+	s1 := AsLinkedSet([]int{5, 1, 4, 2})
+	s2 := AsLinkedSet([]int{2, 6, 5, 7})
+
+	tests := []struct {
+		name string
+		got  *LinkedSet[int]
+		want []int
+	}{
+		{"union", LinkedUnion(s1, s2), []int{5, 1, 4, 2, 6, 7}},
+		{"intersection", LinkedIntersection(s1, s2), []int{5, 2}},
+		{"difference", LinkedDifference(s1, s2), []int{1, 4}},
+		{"symmetric difference", LinkedSymmetricDifference(s1, s2), []int{1, 4, 6, 7}},
+	}
+	for _, tc := range tests {
+		if got := tc.got.Slice(); !slices.Equal(got, tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -2,23 +2,33 @@ package collections
 
 import (
 	"fmt"
-	"math/rand"
+	"math/rand/v2"
+	"slices"
 	"sort"
 )
 
 // List is an implementation of a list using a slice.
-type List[T comparable] []T
+//
+// All List methods which take an index accept "Python-style" indices, which
+// can be negative: an index i < 0 is interpreted as Size()+i, so -1 refers to
+// the last element of the List, -2 the second last, etc.
+//
+// A List can hold elements of any type. Methods which need to compare
+// elements for equality (ContainsFunc, FindFunc, RemoveAllFunc) take an
+// equality function; for Lists of comparable elements, the ListContains,
+// ListFind and ListRemoveAll functions use == instead.
+type List[T any] []T
 
 // Constructors
 
 // NewList makes a new List with the specified initial capacity.
-func NewList[T comparable](capacity int) *List[T] {
+func NewList[T any](capacity int) *List[T] {
 	l := make(List[T], 0, capacity)
 	return &l
 }
 
 // AsList returns a List backed by the given slice.
-func AsList[T comparable](s []T) *List[T] {
+func AsList[T any](s []T) *List[T] {
 	l := List[T](s)
 	return &l
 }
@@ -50,21 +60,23 @@ func (l *List[T]) Capacity() int {
 	return cap(*l)
 }
 
-// Contains returns true if the given element is in the List.
-func (l *List[T]) Contains(t T) bool {
+// ContainsFunc returns true if the given element is in the List, using eq
+// to compare elements for equality.
+func (l *List[T]) ContainsFunc(t T, eq func(s, t T) bool) bool {
 	for _, s := range *l {
-		if s == t {
+		if eq(s, t) {
 			return true
 		}
 	}
 	return false
 }
 
-// Find returns the first index at which the given element appears,
-// or returns an error if the element is not found.
-func (l *List[T]) Find(t T) (pos int, err error) {
+// FindFunc returns the first index at which the given element appears,
+// using eq to compare elements for equality. It returns an error if the
+// element is not found.
+func (l *List[T]) FindFunc(t T, eq func(s, t T) bool) (pos int, err error) {
 	for i, s := range *l {
-		if s == t {
+		if eq(s, t) {
 			pos = i
 			return
 		}
@@ -77,15 +89,25 @@ func (l *List[T]) Find(t T) (pos int, err error) {
 // Get returns the element at index pos in this List.
 // It returns an error if the given index is out of bounds.
 func (l *List[T]) Get(pos int) (t T, err error) {
-	if pos < 0 || pos >= l.Size() {
-		err = l.errIndexOutOfBounds(pos)
+	i, err := l.normaliseElementIndex(pos)
+	if err != nil {
 		return
 	}
 
-	t = (*l)[pos]
+	t = (*l)[i]
 	return
 }
 
+// At returns the element at index pos in this List. Unlike Get, it panics if
+// the given index is out of bounds.
+func (l *List[T]) At(pos int) T {
+	t, err := l.Get(pos)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
 // Basic (mutating) functions
 
 // Append appends the given elements to the end of the List.
@@ -93,11 +115,14 @@ func (l *List[T]) Append(t ...T) {
 	*l = append(*l, t...)
 }
 
-// Insert inserts t at position pos in this List.
+// Insert inserts t at position pos in this List, so that t is located at
+// index pos afterwards. pos may equal Size(), in which case t is appended.
+// A negative pos inserts t before the element currently at that index.
 // It returns an error if the given index is out of bounds.
 func (l *List[T]) Insert(t T, pos int) error {
-	if pos < 0 || pos > l.Size() {
-		return l.errIndexOutOfBounds(pos)
+	pos, err := l.normaliseIndex(pos)
+	if err != nil {
+		return err
 	}
 
 	var z T
@@ -110,8 +135,9 @@ func (l *List[T]) Insert(t T, pos int) error {
 // Set replaces the element at position pos with t.
 // It returns an error if the given index is out of bounds.
 func (l *List[T]) Set(pos int, t T) error {
-	if pos < 0 || pos >= l.Size() {
-		return l.errIndexOutOfBounds(pos)
+	pos, err := l.normaliseElementIndex(pos)
+	if err != nil {
+		return err
 	}
 
 	(*l)[pos] = t
@@ -123,35 +149,40 @@ func (l *List[T]) Set(pos int, t T) error {
 // It returns the removed element, or an error if the given index is out of
 // bounds.
 func (l *List[T]) Remove(pos int) (t T, err error) {
-	if pos < 0 || pos >= l.Size() {
-		err = l.errIndexOutOfBounds(pos)
+	i, err := l.normaliseElementIndex(pos)
+	if err != nil {
 		return
 	}
 
-	t = (*l)[pos]
-	*l = append((*l)[:pos], (*l)[pos+1:]...)
+	t = (*l)[i]
+	*l = append((*l)[:i], (*l)[i+1:]...)
 	return
 }
 
-// RemoveAll removes all occurrences of the given element in the List.
-func (l *List[T]) RemoveAll(t T) {
-	n := 0
-	for _, s := range *l {
-		if s == t {
-			(*l)[n] = s
-			n++
-		}
-	}
-	*l = (*l)[:n]
+// RemoveAllFunc removes all occurrences of the given element in the List,
+// using eq to compare elements for equality.
+func (l *List[T]) RemoveAllFunc(t T, eq func(s, t T) bool) {
+	l.RemoveIf(func(s T) bool { return eq(s, t) })
+}
+
+// RemoveIf removes all elements t in this List such that f(t) == true,
+// preserving the order of the remaining elements. It returns the number of
+// elements removed. The List is compacted in place, without allocating.
+func (l *List[T]) RemoveIf(f func(T) bool) int {
+	return l.FilterInPlace(func(_ int, t T) bool { return !f(t) })
+}
+
+// RetainIf removes all elements t in this List such that f(t) == false,
+// preserving the order of the remaining elements. It returns the number of
+// elements removed. The List is compacted in place, without allocating.
+func (l *List[T]) RetainIf(f func(T) bool) int {
+	return l.FilterInPlace(func(_ int, t T) bool { return f(t) })
 }
 
 // Slice slices this List at the given indices, removing all elements with
 // index smaller than low or at least high.
 // It returns an error if either index is out of bounds, or if low is
 // greater than high.
-//
-// Slice accepts "Python-style" indices which can be negative:
-// -1 refers to the last element of the List, -2 the second last, etc.
 func (l *List[T]) Slice(low, high int) error {
 	low, high, err := l.checkIndices(low, high)
 	if err != nil {
@@ -162,6 +193,41 @@ func (l *List[T]) Slice(low, high int) error {
 	return nil
 }
 
+// SliceStep slices this List like Slice, but keeps only every step-th
+// element, starting at low. If step is negative, the List is traversed
+// backwards from low down to (but not including) high, reversing the order
+// of the kept elements.
+//
+// For a negative step, low and high may also be -Size()-1, which refers to
+// the (non-existent) position just before the start of the List. So
+//
+//	l.SliceStep(-1, -l.Size()-1, -1)
+//
+// reverses l.
+//
+// It returns an error if step is zero, if either index is out of bounds, or
+// if the indices are in the wrong order for the given step.
+func (l *List[T]) SliceStep(low, high, step int) error {
+	indices, err := l.stepIndices(low, high, step)
+	if err != nil {
+		return err
+	}
+
+	// Each kept element is written to an index no greater than the one it
+	// was read from when step > 0, so the List can be compacted in place.
+	// For step < 0, elements must be copied to avoid overwriting.
+	src := *l
+	if step < 0 {
+		src = make([]T, l.Size())
+		copy(src, *l)
+	}
+	for n, i := range indices {
+		(*l)[n] = src[i]
+	}
+	*l = (*l)[:len(indices)]
+	return nil
+}
+
 // Copying functions
 
 // Copy returns a copy of the given List.
@@ -170,13 +236,22 @@ func (l *List[T]) Copy() *List[T] {
 	return lcopy
 }
 
+// CopyDeep returns a deep copy of the given List, in which each element is
+// copied using clone. If clone is nil, elements implementing Cloner[T] are
+// copied using their Clone method, and other elements are copied as-is.
+func (l *List[T]) CopyDeep(clone func(t T) T) *List[T] {
+	clone = cloneFunc(clone)
+	cp := make(List[T], len(*l))
+	for i, t := range *l {
+		cp[i] = clone(t)
+	}
+	return &cp
+}
+
 // CopyPart returns a partial copy of the given List, i.e. it copies the part
 // of the List starting at low and ending at high-1.
 // It returns an error if either index is out of bounds, or if low is
 // greater than high.
-//
-// CopyPart accepts "Python-style" indices which can be negative:
-// -1 refers to the last element of the List, -2 the second last, etc.
 func (l *List[T]) CopyPart(low, high int) (*List[T], error) {
 	low, high, err := l.checkIndices(low, high)
 	if err != nil {
@@ -204,7 +279,7 @@ func (l *List[T]) Count(f func(int, T) bool) int {
 }
 
 // Filter returns a new List containing only the elements t in this List
-// such that f(index(t), t) == false.
+// such that f(index(t), t) == true.
 func (l *List[T]) Filter(f func(int, T) bool) *List[T] {
 	fList := NewList[T](l.Size())
 	for i, t := range *l {
@@ -215,6 +290,25 @@ func (l *List[T]) Filter(f func(int, T) bool) *List[T] {
 	return fList
 }
 
+// FilterInPlace removes all elements t in this List such that
+// f(index(t), t) == false, where index(t) is the original index of t. It is
+// the in-place equivalent of Filter, and returns the number of elements
+// removed. The List is compacted without allocating, and the vacated slots
+// at the end of the underlying array are zeroed.
+func (l *List[T]) FilterInPlace(f func(int, T) bool) int {
+	n := 0
+	for i, t := range *l {
+		if f(i, t) {
+			(*l)[n] = t
+			n++
+		}
+	}
+	removed := len(*l) - n
+	clear((*l)[n:])
+	*l = (*l)[:n]
+	return removed
+}
+
 // Ordering methods
 
 // Shuffle randomises the order of elements using rand.Shuffle.
@@ -224,6 +318,14 @@ func (l *List[T]) Shuffle() {
 	})
 }
 
+// ShuffleWith randomises the order of elements, drawing randomness from the
+// given source. If src is nil, the global random source is used.
+func (l *List[T]) ShuffleWith(src rand.Source) {
+	randFrom(src).Shuffle(l.Size(), func(i, j int) {
+		(*l)[i], (*l)[j] = (*l)[j], (*l)[i]
+	})
+}
+
 // Sort sorts this List according to the provided less function.
 func (l *List[T]) Sort(less func(s, t T) bool) {
 	sort.SliceStable(*l, func(i, j int) bool {
@@ -231,6 +333,182 @@ func (l *List[T]) Sort(less func(s, t T) bool) {
 	})
 }
 
+// SortUnstable sorts this List according to the provided less function.
+// Unlike Sort, the relative order of equal elements may not be preserved,
+// but SortUnstable is generally faster.
+func (l *List[T]) SortUnstable(less func(s, t T) bool) {
+	slices.SortFunc(*l, func(s, t T) int {
+		switch {
+		case less(s, t):
+			return -1
+		case less(t, s):
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// Random sampling methods
+
+// RandomElement returns an element of this List chosen uniformly at random
+// using the given source. If src is nil, the global random source is used.
+// It returns an error if the List is empty.
+func (l *List[T]) RandomElement(src rand.Source) (t T, err error) {
+	if l.IsEmpty() {
+		err = errListEmpty
+		return
+	}
+
+	t = (*l)[randFrom(src).IntN(l.Size())]
+	return
+}
+
+// Sample returns a new List containing k elements of this List chosen
+// uniformly at random, without replacement, using the given source. If src
+// is nil, the global random source is used. The returned elements are in
+// random order.
+// It returns an error if k is negative or greater than the size of this List.
+func (l *List[T]) Sample(k int, src rand.Source) (*List[T], error) {
+	if k < 0 {
+		return nil, errNegativeSampleSize(k)
+	}
+	if k > l.Size() {
+		return nil, errSampleTooLarge(k, l.Size())
+	}
+
+	// Partial Fisher-Yates shuffle over the indices, so this List is not
+	// modified.
+	r := randFrom(src)
+	indices := make([]int, l.Size())
+	for i := range indices {
+		indices[i] = i
+	}
+	sample := NewList[T](k)
+	for i := 0; i < k; i++ {
+		j := i + r.IntN(len(indices)-i)
+		indices[i], indices[j] = indices[j], indices[i]
+		sample.Append((*l)[indices[i]])
+	}
+	return sample, nil
+}
+
+// WeightedRandomElement returns an element t of this List chosen at random
+// with probability proportional to weight(index(t), t), using the given
+// source. If src is nil, the global random source is used. Elements with
+// non-positive weight are never chosen.
+// It returns an error if no element has positive weight.
+func (l *List[T]) WeightedRandomElement(weight func(int, T) float64, src rand.Source) (t T, err error) {
+	total := 0.0
+	weights := make([]float64, l.Size())
+	for i, s := range *l {
+		if w := weight(i, s); w > 0 {
+			weights[i] = w
+			total += w
+		}
+	}
+	if total == 0 {
+		err = errNoPositiveWeights
+		return
+	}
+
+	x := randFrom(src).Float64() * total
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		last = i
+		if x < w {
+			break
+		}
+		x -= w
+	}
+	// If rounding error carries x past the final weight, last is the final
+	// element with positive weight.
+	t = (*l)[last]
+	return
+}
+
+// WeightedSample returns a new List containing k elements of this List
+// chosen at random without replacement, where at each step an element t is
+// chosen with probability proportional to weight(index(t), t). Randomness
+// is drawn from the given source; if src is nil, the global random source is
+// used. Elements with non-positive weight are never chosen. The returned
+// elements are in the order they were chosen.
+// It returns an error if k is negative or greater than the number of
+// elements with positive weight.
+func (l *List[T]) WeightedSample(k int, weight func(int, T) float64, src rand.Source) (*List[T], error) {
+	if k < 0 {
+		return nil, errNegativeSampleSize(k)
+	}
+	keys, positive := weightedKeys(l, weight, randFrom(src))
+	if k > positive {
+		return nil, errSampleTooLarge(k, positive)
+	}
+
+	indices := make([]int, l.Size())
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return keys[indices[i]] > keys[indices[j]]
+	})
+	sample := NewList[T](k)
+	for _, i := range indices[:k] {
+		sample.Append((*l)[i])
+	}
+	return sample, nil
+}
+
+// Iteration
+
+type listIterator[T any] struct {
+	l     *List[T]
+	index int
+}
+
+func (i *listIterator[T]) HasNext() bool {
+	return i.index < i.l.Size()
+}
+
+func (i *listIterator[T]) Next() T {
+	t := (*i.l)[i.index]
+	i.index++
+	return t
+}
+
+// Iterate returns an Iterator iterating over the given List in order.
+func (l *List[T]) Iterate() Iterator[T] {
+	return &listIterator[T]{
+		l:     l,
+		index: 0,
+	}
+}
+
+// Functions on Lists of comparable elements
+
+// ListContains returns true if the given element is in the List.
+func ListContains[T comparable](l *List[T], t T) bool {
+	return l.ContainsFunc(t, equal[T])
+}
+
+// ListFind returns the first index at which the given element appears in the
+// List, or returns an error if the element is not found.
+func ListFind[T comparable](l *List[T], t T) (int, error) {
+	return l.FindFunc(t, equal[T])
+}
+
+// ListRemoveAll removes all occurrences of the given element in the List.
+func ListRemoveAll[T comparable](l *List[T], t T) {
+	l.RemoveAllFunc(t, equal[T])
+}
+
+// equal compares two comparable values using ==.
+func equal[T comparable](s, t T) bool {
+	return s == t
+}
+
 // Internal methods
 
 func (l *List[T]) checkIndices(low, high int) (lo int, hi int, err error) {
@@ -248,8 +526,72 @@ func (l *List[T]) checkIndices(low, high int) (lo int, hi int, err error) {
 	return
 }
 
+// normaliseIndex converts a Python-style index into a slice bound, i.e. an
+// index in the range [0, Size()].
 func (l *List[T]) normaliseIndex(i int) (int, error) {
-	if i <= -l.Size() || i > l.Size() {
+	if i < -l.Size() || i > l.Size() {
+		return 0, l.errIndexOutOfBounds(i)
+	}
+	if i < 0 {
+		i += l.Size()
+	}
+	return i, nil
+}
+
+// normaliseElementIndex converts a Python-style index into the index of an
+// element, i.e. an index in the range [0, Size()).
+func (l *List[T]) normaliseElementIndex(i int) (int, error) {
+	if i < -l.Size() || i >= l.Size() {
+		return 0, l.errIndexOutOfBounds(i)
+	}
+	if i < 0 {
+		i += l.Size()
+	}
+	return i, nil
+}
+
+// stepIndices returns the indices of the elements selected by
+// SliceStep(low, high, step).
+func (l *List[T]) stepIndices(low, high, step int) ([]int, error) {
+	if step == 0 {
+		return nil, errZeroStep
+	}
+	if step > 0 {
+		lo, hi, err := l.checkIndices(low, high)
+		if err != nil {
+			return nil, err
+		}
+		indices := make([]int, 0, (hi-lo+step-1)/step)
+		for i := lo; i < hi; i += step {
+			indices = append(indices, i)
+		}
+		return indices, nil
+	}
+
+	// step < 0: indices lie in the range [-1, Size()), where -1 is the
+	// position before the start of the List.
+	lo, err := l.normaliseReverseIndex(low)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := l.normaliseReverseIndex(high)
+	if err != nil {
+		return nil, err
+	}
+	if lo < hi {
+		return nil, l.errLowBelowHigh(lo, hi)
+	}
+	indices := make([]int, 0, (lo-hi-step-1)/(-step))
+	for i := lo; i > hi; i += step {
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
+
+// normaliseReverseIndex converts a Python-style index into a bound for
+// reverse traversal, i.e. an index in the range [-1, Size()).
+func (l *List[T]) normaliseReverseIndex(i int) (int, error) {
+	if i < -l.Size()-1 || i >= l.Size() {
 		return 0, l.errIndexOutOfBounds(i)
 	}
 	if i < 0 {
@@ -271,3 +613,13 @@ func (l *List[T]) errLowAboveHigh(low, high int) error {
 func (l *List[T]) errElementNotFound(t T) error {
 	return fmt.Errorf("element not found in List: %v", t)
 }
+
+func (l *List[T]) errLowBelowHigh(low, high int) error {
+	return fmt.Errorf("low index %d less than high index %d for negative step", low, high)
+}
+
+var errZeroStep = fmt.Errorf("slice step cannot be zero")
+
+var errListEmpty = fmt.Errorf("list is empty")
+
+var errNoPositiveWeights = fmt.Errorf("no element has positive weight")
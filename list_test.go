@@ -0,0 +1,295 @@
+package collections
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// indexCases covers the boundaries of Python-style indexing on a List of
+// size 3: -Size()-1, -Size(), -1, Size()-1 and Size().
+var indexCases = []struct {
+	pos     int
+	valid   bool // whether pos refers to an element
+	element int  // the element at pos, if valid
+	insert  int  // the index at which Insert(pos) places its element, or -1
+}{
+	{pos: -4, valid: false, insert: -1},
+	{pos: -3, valid: true, element: 10, insert: 0},
+	{pos: -1, valid: true, element: 30, insert: 2},
+	{pos: 2, valid: true, element: 30, insert: 2},
+	{pos: 3, valid: false, insert: 3},
+}
+
+func newIndexTestList() *List[int] {
+	return AsList([]int{10, 20, 30})
+}
+
+func TestListGetIndices(t *testing.T) {
+	for _, tc := range indexCases {
+		got, err := newIndexTestList().Get(tc.pos)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("Get(%d): expected error, got %d", tc.pos, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.element {
+			t.Errorf("Get(%d) = %d, %v; want %d", tc.pos, got, err, tc.element)
+		}
+	}
+}
+
+func TestListAtIndices(t *testing.T) {
+	for _, tc := range indexCases {
+		func() {
+			defer func() {
+				if r := recover(); (r != nil) == tc.valid {
+					t.Errorf("At(%d): unexpected panic state %v", tc.pos, r)
+				}
+			}()
+			if got := newIndexTestList().At(tc.pos); got != tc.element {
+				t.Errorf("At(%d) = %d, want %d", tc.pos, got, tc.element)
+			}
+		}()
+	}
+}
+
+func TestListSetIndices(t *testing.T) {
+	for _, tc := range indexCases {
+		l := newIndexTestList()
+		err := l.Set(tc.pos, 99)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("Set(%d): expected error", tc.pos)
+			}
+			if !slices.Equal(*l, []int{10, 20, 30}) {
+				t.Errorf("Set(%d): List modified on error: %v", tc.pos, *l)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%d): unexpected error %v", tc.pos, err)
+		}
+		if got := l.At(tc.pos); got != 99 {
+			t.Errorf("Set(%d): element is %d afterwards, want 99", tc.pos, got)
+		}
+	}
+}
+
+func TestListInsertIndices(t *testing.T) {
+	for _, tc := range indexCases {
+		l := newIndexTestList()
+		err := l.Insert(99, tc.pos)
+		if tc.insert < 0 {
+			if err == nil {
+				t.Errorf("Insert(%d): expected error", tc.pos)
+			}
+			continue
+		}
+		want := slices.Insert([]int{10, 20, 30}, tc.insert, 99)
+		if err != nil || !slices.Equal(*l, want) {
+			t.Errorf("Insert(%d) = %v, %v; want %v", tc.pos, *l, err, want)
+		}
+	}
+}
+
+func TestListRemoveIndices(t *testing.T) {
+	for _, tc := range indexCases {
+		l := newIndexTestList()
+		got, err := l.Remove(tc.pos)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("Remove(%d): expected error", tc.pos)
+			}
+			continue
+		}
+		if err != nil || got != tc.element || l.Size() != 2 || slices.Contains(*l, tc.element) {
+			t.Errorf("Remove(%d) = %d, %v leaving %v; want %d removed", tc.pos, got, err, *l, tc.element)
+		}
+	}
+}
+
+func TestListNonComparable(t *testing.T) {
+	l := AsList([][]int{{1, 2}, {3}, nil, {1, 2}, {4, 5, 6}})
+	eq := func(s, t []int) bool { return slices.Equal(s, t) }
+
+	if !l.ContainsFunc([]int{3}, eq) || l.ContainsFunc([]int{9, 9}, eq) {
+		t.Errorf("ContainsFunc did not compare elements with eq")
+	}
+	if pos, err := l.FindFunc([]int{1, 2}, eq); err != nil || pos != 0 {
+		t.Errorf("FindFunc([1 2]) = %d, %v; want the first occurrence, 0", pos, err)
+	}
+	_, err := l.FindFunc([]int{9, 9}, eq)
+	if err == nil || err.Error() != "element not found in List: [9 9]" {
+		t.Errorf("FindFunc of a missing element gave error %v", err)
+	}
+
+	cp := l.Copy()
+	cp.Set(0, []int{7})
+	if !slices.Equal(l.At(0), []int{1, 2}) || cp.Size() != 5 {
+		t.Errorf("modifying a copy changed the original: %v", *l)
+	}
+
+	l.RemoveAllFunc([]int{1, 2}, eq)
+	if !slices.EqualFunc(*l, [][]int{{3}, nil, {4, 5, 6}}, slices.Equal) {
+		t.Errorf("RemoveAllFunc([1 2]) left %v", *l)
+	}
+	if cp.Size() != 5 || !slices.Equal(cp.At(-1), []int{4, 5, 6}) {
+		t.Errorf("RemoveAllFunc on the original changed the copy: %v", *cp)
+	}
+
+	funcs := NewList[func() int](0)
+	funcs.Append(func() int { return 1 }, func() int { return 2 })
+	if f, err := funcs.Get(-1); err != nil || f() != 2 {
+		t.Errorf("Get(-1) on a List of funcs failed: %v", err)
+	}
+
+	// the comparable-only helpers still work on comparable types
+	ints := AsList([]int{4, 1, 4, 2})
+	if !ListContains(ints, 2) || ListContains(ints, 3) {
+		t.Errorf("ListContains gave the wrong result")
+	}
+	if pos, err := ListFind(ints, 4); err != nil || pos != 0 {
+		t.Errorf("ListFind(4) = %d, %v; want 0", pos, err)
+	}
+	if _, err := ListFind(ints, 3); err == nil {
+		t.Errorf("ListFind of a missing element returned no error")
+	}
+	ListRemoveAll(ints, 4)
+	if !slices.Equal(*ints, []int{1, 2}) {
+		t.Errorf("ListRemoveAll(4) left %v", *ints)
+	}
+}
+
+func TestListSliceStep(t *testing.T) {
+	tests := []struct {
+		elems           []int
+		low, high, step int
+		want            []int // nil if an error is expected
+	}{
+		{[]int{0, 1, 2, 3, 4, 5}, 0, 6, 1, []int{0, 1, 2, 3, 4, 5}},
+		{[]int{0, 1, 2, 3, 4, 5}, -6, 6, 1, []int{0, 1, 2, 3, 4, 5}},
+		{[]int{0, 1, 2, 3, 4, 5}, 0, 6, 2, []int{0, 2, 4}},
+		{[]int{0, 1, 2, 3, 4, 5}, 1, -1, 2, []int{1, 3}},
+		{[]int{0, 1, 2, 3, 4, 5}, 0, 6, 4, []int{0, 4}},
+		{[]int{0, 1, 2, 3, 4, 5}, 3, 3, 1, []int{}},
+		{[]int{0, 1, 2, 3, 4, 5}, -1, -7, -1, []int{5, 4, 3, 2, 1, 0}},
+		{[]int{0, 1, 2, 3, 4, 5}, 5, 0, -2, []int{5, 3, 1}},
+		{[]int{0, 1, 2, 3, 4, 5}, -1, -7, -2, []int{5, 3, 1}},
+		{[]int{0, 1, 2, 3, 4, 5}, 2, 2, -1, []int{}},
+		{[]int{0, 1, 2, 3, 4, 5}, 0, 6, 0, nil},
+		{[]int{0, 1, 2, 3, 4, 5}, 0, 7, 1, nil},
+		{[]int{0, 1, 2, 3, 4, 5}, -7, 6, 1, nil},
+		{[]int{0, 1, 2, 3, 4, 5}, 4, 2, 1, nil},
+		{[]int{0, 1, 2, 3, 4, 5}, 2, 4, -1, nil},
+		{[]int{0, 1, 2, 3, 4, 5}, 6, 0, -1, nil},
+		{[]int{}, 0, 0, 1, []int{}},
+		{[]int{}, 0, 0, 3, []int{}},
+		{[]int{}, -1, -1, -1, []int{}},
+		{[]int{}, 0, 1, 1, nil},
+		{[]int{}, 0, 0, -1, nil},
+	}
+
+	for _, tc := range tests {
+		l := AsList(slices.Clone(tc.elems))
+		err := l.SliceStep(tc.low, tc.high, tc.step)
+		if tc.want == nil {
+			if err == nil {
+				t.Errorf("%v.SliceStep(%d, %d, %d): expected error, got %v",
+					tc.elems, tc.low, tc.high, tc.step, *l)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(*l, tc.want) {
+			t.Errorf("%v.SliceStep(%d, %d, %d) = %v, %v; want %v",
+				tc.elems, tc.low, tc.high, tc.step, *l, err, tc.want)
+		}
+	}
+}
+
+func TestListRemoveAll(t *testing.T) {
+	l := AsList([]int{1, 2, 1, 3, 1, 1, 4})
+	ListRemoveAll(l, 1)
+	if !slices.Equal(*l, []int{2, 3, 4}) {
+		t.Errorf("ListRemoveAll(l, 1) = %v, want [2 3 4]", *l)
+	}
+	ListRemoveAll(l, 5)
+	if !slices.Equal(*l, []int{2, 3, 4}) {
+		t.Errorf("ListRemoveAll of a missing element changed the List: %v", *l)
+	}
+
+	words := AsList([]string{"Go", "go", "GO", "rust"})
+	words.RemoveAllFunc("go", strings.EqualFold)
+	if !slices.Equal(*words, []string{"rust"}) {
+		t.Errorf("RemoveAllFunc = %v, want [rust]", *words)
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	l := AsList([]int{5, 6, 7, 8, 9})
+	got := l.Filter(func(i, x int) bool { return x%2 == 0 || i == 0 })
+	if !slices.Equal(*got, []int{5, 6, 8}) {
+		t.Errorf("Filter kept %v, want the elements where f is true: [5 6 8]", *got)
+	}
+	if !slices.Equal(*l, []int{5, 6, 7, 8, 9}) {
+		t.Errorf("Filter modified its receiver: %v", *l)
+	}
+}
+
+func TestListFilterInPlace(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  func(l *List[*int]) int
+		keep    []int // indices of the elements kept
+		removed int
+	}{
+		{"FilterInPlace", func(l *List[*int]) int {
+			return l.FilterInPlace(func(i int, p *int) bool { return i%3 != 1 })
+		}, []int{0, 2, 3, 5}, 2},
+		{"RetainIf", func(l *List[*int]) int {
+			return l.RetainIf(func(p *int) bool { return *p >= 4 })
+		}, []int{4, 5}, 4},
+		{"RemoveIf", func(l *List[*int]) int {
+			return l.RemoveIf(func(p *int) bool { return *p%2 == 0 })
+		}, []int{1, 3, 5}, 3},
+		{"RemoveIf none", func(l *List[*int]) int {
+			return l.RemoveIf(func(p *int) bool { return false })
+		}, []int{0, 1, 2, 3, 4, 5}, 0},
+		{"RetainIf none", func(l *List[*int]) int {
+			return l.RetainIf(func(p *int) bool { return false })
+		}, []int{}, 6},
+	}
+
+	for _, tt := range tests {
+		elems := make([]*int, 6, 8)
+		for i := range elems {
+			elems[i] = new(int)
+			*elems[i] = i
+		}
+		orig := slices.Clone(elems)
+		l := AsList(elems)
+		first, capBefore := &elems[0], cap(*l)
+
+		if removed := tt.filter(l); removed != tt.removed {
+			t.Errorf("%s removed %d elements, want %d", tt.name, removed, tt.removed)
+		}
+		want := make([]*int, len(tt.keep))
+		for i, k := range tt.keep {
+			want[i] = orig[k]
+		}
+		if !slices.Equal(*l, want) {
+			t.Errorf("%s kept %d elements, want indices %v", tt.name, l.Size(), tt.keep)
+		}
+		// no reallocation: the same backing array and capacity
+		if cap(*l) != capBefore || &(*l)[:1][0] != first {
+			t.Errorf("%s reallocated the List", tt.name)
+		}
+		// the vacated slots are zeroed
+		for i, p := range elems[len(tt.keep):] {
+			if p != nil {
+				t.Errorf("%s left a pointer in vacated slot %d", tt.name, len(tt.keep)+i)
+			}
+		}
+	}
+}
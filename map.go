@@ -5,6 +5,12 @@ import "fmt"
 // Map is an implementation of a map using Go's hashmap.
 type Map[K comparable, V any] map[K]V
 
+// Entry is a key-value pair in a Map.
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
 // Constructors
 
 // NewMap makes a new Map with the specified initial capacity.
@@ -61,6 +67,33 @@ func (m *Map[K, V]) Keys() *List[K] {
 	return keys
 }
 
+// GetOrDefault returns the value associated with k, or def if k is not a key
+// in this Map.
+func (m *Map[K, V]) GetOrDefault(k K, def V) V {
+	if v, ok := (*m)[k]; ok {
+		return v
+	}
+	return def
+}
+
+// Values returns all values present in this Map.
+func (m *Map[K, V]) Values() *List[V] {
+	values := NewList[V](m.Size())
+	for _, v := range *m {
+		values.Append(v)
+	}
+	return values
+}
+
+// Entries returns all key-value pairs present in this Map.
+func (m *Map[K, V]) Entries() *List[Entry[K, V]] {
+	entries := NewList[Entry[K, V]](m.Size())
+	for k, v := range *m {
+		entries.Append(Entry[K, V]{k, v})
+	}
+	return entries
+}
+
 // Basic (mutating) functions
 
 // Set adds the given key-value pair to this Map. If there is already a value
@@ -77,6 +110,94 @@ func (m *Map[K, V]) Remove(k K) bool {
 	return ret
 }
 
+// Clear removes all entries from this Map.
+func (m *Map[K, V]) Clear() {
+	clear(*m)
+}
+
+// GetOrSet returns the value associated with k. If k is not a key in this
+// Map, GetOrSet first associates k with the value returned by f.
+// f is only called if k is not in the Map.
+func (m *Map[K, V]) GetOrSet(k K, f func() V) V {
+	if v, ok := (*m)[k]; ok {
+		return v
+	}
+	v := f()
+	m.Set(k, v)
+	return v
+}
+
+// Compute calls f on the value currently associated with k (ok is false if
+// k is not in the Map). If f returns keep == true, the returned value is
+// associated with k; otherwise, k is removed from the Map.
+// Compute returns the new value associated with k, and whether k is still in
+// the Map.
+func (m *Map[K, V]) Compute(k K, f func(v V, ok bool) (newV V, keep bool)) (V, bool) {
+	v, ok := (*m)[k]
+	newV, keep := f(v, ok)
+	if !keep {
+		delete(*m, k)
+		var z V
+		return z, false
+	}
+	m.Set(k, newV)
+	return newV, true
+}
+
+// ComputeIfAbsent associates k with f(k) if k is not already in the Map.
+// It returns the value associated with k afterwards.
+func (m *Map[K, V]) ComputeIfAbsent(k K, f func(K) V) V {
+	return m.GetOrSet(k, func() V { return f(k) })
+}
+
+// ComputeIfPresent calls f on k and its associated value, if k is in the
+// Map. If f returns keep == true, the returned value is associated with k;
+// otherwise, k is removed from the Map. If k is not in the Map, f is not
+// called.
+// ComputeIfPresent returns the new value associated with k, and whether k is
+// in the Map afterwards.
+func (m *Map[K, V]) ComputeIfPresent(k K, f func(K, V) (newV V, keep bool)) (V, bool) {
+	v, ok := (*m)[k]
+	if !ok {
+		return v, false
+	}
+	return m.Compute(k, func(v V, _ bool) (V, bool) { return f(k, v) })
+}
+
+// PutAll adds all entries of other to this Map, overwriting the values of
+// existing keys.
+func (m *Map[K, V]) PutAll(other *Map[K, V]) {
+	for k, v := range *other {
+		m.Set(k, v)
+	}
+}
+
+// Merge adds all entries of other to this Map. If a key is in both Maps,
+// its new value is resolve(k, v1, v2), where v1 is the value in this Map and
+// v2 is the value in other.
+func (m *Map[K, V]) Merge(other *Map[K, V], resolve func(k K, v1, v2 V) V) {
+	for k, v2 := range *other {
+		if v1, ok := (*m)[k]; ok {
+			m.Set(k, resolve(k, v1, v2))
+		} else {
+			m.Set(k, v2)
+		}
+	}
+}
+
+// RemoveIf removes all entries (k, v) of this Map such that f(k, v) == true.
+// It returns the number of entries removed.
+func (m *Map[K, V]) RemoveIf(f func(K, V) bool) int {
+	removed := 0
+	for k, v := range *m {
+		if f(k, v) {
+			delete(*m, k)
+			removed++
+		}
+	}
+	return removed
+}
+
 // Copying functions
 
 // Copy returns a copy of the given Map.
@@ -88,6 +209,66 @@ func (m *Map[K, V]) Copy() *Map[K, V] {
 	return cp
 }
 
+// CopyDeep returns a deep copy of the given Map, in which each value is
+// copied using clone. Keys are copied as-is. If clone is nil, values
+// implementing Cloner[V] are copied using their Clone method, and other
+// values are copied as-is.
+func (m *Map[K, V]) CopyDeep(clone func(v V) V) *Map[K, V] {
+	clone = cloneFunc(clone)
+	cp := NewMap[K, V](m.Size())
+	for k, v := range *m {
+		cp.Set(k, clone(v))
+	}
+	return cp
+}
+
+// Functional methods
+
+// FilterKeys returns a new Map containing only the entries (k, v) of this
+// Map such that f(k) == true.
+func (m *Map[K, V]) FilterKeys(f func(K) bool) *Map[K, V] {
+	fMap := NewMap[K, V](0)
+	for k, v := range *m {
+		if f(k) {
+			fMap.Set(k, v)
+		}
+	}
+	return fMap
+}
+
+// FilterValues returns a new Map containing only the entries (k, v) of this
+// Map such that f(v) == true.
+func (m *Map[K, V]) FilterValues(f func(V) bool) *Map[K, V] {
+	fMap := NewMap[K, V](0)
+	for k, v := range *m {
+		if f(v) {
+			fMap.Set(k, v)
+		}
+	}
+	return fMap
+}
+
+// MapValues returns a new Map with the same keys as the given Map, where
+// each key k is associated with f(k, v), v being the value of k in m.
+func MapValues[K comparable, V, W any](m *Map[K, V], f func(K, V) W) *Map[K, W] {
+	mapped := NewMap[K, W](m.Size())
+	for k, v := range *m {
+		mapped.Set(k, f(k, v))
+	}
+	return mapped
+}
+
+// Invert returns a new Map associating each value of the given Map with its
+// key. If several keys have the same value, it is unspecified which of them
+// the value is associated with in the inverted Map.
+func Invert[K, V comparable](m *Map[K, V]) *Map[V, K] {
+	inv := NewMap[V, K](m.Size())
+	for k, v := range *m {
+		inv.Set(v, k)
+	}
+	return inv
+}
+
 // Iteration
 
 type mapIterator[K comparable, V any] struct {
@@ -0,0 +1,211 @@
+package collections
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMapAccessors(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1, "b": 2, "c": 3})
+
+	if got := m.GetOrDefault("b", -1); got != 2 {
+		t.Errorf("GetOrDefault(present) = %d, want 2", got)
+	}
+	if got := m.GetOrDefault("z", -1); got != -1 {
+		t.Errorf("GetOrDefault(missing) = %d, want -1", got)
+	}
+	if m.Contains("z") {
+		t.Errorf("GetOrDefault added a missing key")
+	}
+
+	values := *m.Values()
+	slices.Sort(values)
+	if !slices.Equal(values, []int{1, 2, 3}) {
+		t.Errorf("Values() = %v", values)
+	}
+
+	entries := *m.Entries()
+	slices.SortFunc(entries, func(a, b Entry[string, int]) int { return strings.Compare(a.Key, b.Key) })
+	want := []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
+	if !slices.Equal(entries, want) {
+		t.Errorf("Entries() = %v, want %v", entries, want)
+	}
+
+	m.Clear()
+	if !m.IsEmpty() || m.Values().Size() != 0 || m.Entries().Size() != 0 {
+		t.Errorf("Map not empty after Clear: %v", *m)
+	}
+}
+
+func TestMapGetOrSet(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1})
+	calls := 0
+	f := func() int { calls++; return 10 }
+
+	if got := m.GetOrSet("a", f); got != 1 || calls != 0 {
+		t.Errorf("GetOrSet(present) = %d with %d calls, want 1 with 0 calls", got, calls)
+	}
+	if got := m.GetOrSet("b", f); got != 10 || calls != 1 {
+		t.Errorf("GetOrSet(missing) = %d with %d calls, want 10 with 1 call", got, calls)
+	}
+	if v, err := m.Get("b"); err != nil || v != 10 {
+		t.Errorf("GetOrSet did not store the value: %d, %v", v, err)
+	}
+
+	got := m.ComputeIfAbsent("cc", func(k string) int { return len(k) })
+	if got != 2 || m.GetOrDefault("cc", 0) != 2 {
+		t.Errorf("ComputeIfAbsent(missing) = %d", got)
+	}
+	got = m.ComputeIfAbsent("a", func(k string) int {
+		t.Errorf("ComputeIfAbsent called f for a present key")
+		return 0
+	})
+	if got != 1 {
+		t.Errorf("ComputeIfAbsent(present) = %d, want 1", got)
+	}
+}
+
+func TestMapCompute(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1, "b": 2})
+	// count occurrences, removing keys whose count falls to 0
+	incr := func(d int) func(v int, ok bool) (int, bool) {
+		return func(v int, ok bool) (int, bool) {
+			return v + d, v+d != 0
+		}
+	}
+
+	if v, ok := m.Compute("a", incr(1)); v != 2 || !ok {
+		t.Errorf("Compute(present) = %d, %v; want 2, true", v, ok)
+	}
+	if v, ok := m.Compute("z", incr(5)); v != 5 || !ok {
+		t.Errorf("Compute(missing) = %d, %v; want 5, true", v, ok)
+	}
+	if v, ok := m.Compute("b", incr(-2)); v != 0 || ok {
+		t.Errorf("Compute(delete) = %d, %v; want 0, false", v, ok)
+	}
+	if m.Contains("b") {
+		t.Errorf("Compute did not delete b when told to")
+	}
+	var sawOK bool
+	if _, ok := m.Compute("y", func(v int, ok bool) (int, bool) {
+		sawOK = ok
+		return 0, false
+	}); ok || sawOK || m.Contains("y") {
+		t.Errorf("Compute(missing, delete) added y or reported it present")
+	}
+	if want := map[string]int{"a": 2, "z": 5}; !maps.Equal(*m, want) {
+		t.Errorf("after Compute: %v, want %v", *m, want)
+	}
+}
+
+func TestMapComputeIfPresent(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1, "b": 2})
+	double := func(k string, v int) (int, bool) { return 2 * v, true }
+
+	if v, ok := m.ComputeIfPresent("a", double); v != 2 || !ok {
+		t.Errorf("ComputeIfPresent(present) = %d, %v; want 2, true", v, ok)
+	}
+	called := false
+	if v, ok := m.ComputeIfPresent("z", func(k string, v int) (int, bool) {
+		called = true
+		return 1, true
+	}); v != 0 || ok || called {
+		t.Errorf("ComputeIfPresent(missing) = %d, %v (f called: %v); want 0, false, not called", v, ok, called)
+	}
+	if m.Contains("z") {
+		t.Errorf("ComputeIfPresent added a missing key")
+	}
+	if v, ok := m.ComputeIfPresent("b", func(k string, v int) (int, bool) { return v, false }); v != 0 || ok {
+		t.Errorf("ComputeIfPresent(delete) = %d, %v; want 0, false", v, ok)
+	}
+	if want := map[string]int{"a": 2}; !maps.Equal(*m, want) {
+		t.Errorf("after ComputeIfPresent: %v, want %v", *m, want)
+	}
+}
+
+func TestMapPutAllAndMerge(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1, "b": 2})
+	other := AsMap(map[string]int{"b": 20, "c": 30})
+
+	put := m.Copy()
+	put.PutAll(other)
+	if want := map[string]int{"a": 1, "b": 20, "c": 30}; !maps.Equal(*put, want) {
+		t.Errorf("PutAll: %v, want %v", *put, want)
+	}
+
+	var resolved []string
+	m.Merge(other, func(k string, v1, v2 int) int {
+		resolved = append(resolved, k)
+		return v1 + v2
+	})
+	if want := map[string]int{"a": 1, "b": 22, "c": 30}; !maps.Equal(*m, want) {
+		t.Errorf("Merge: %v, want %v", *m, want)
+	}
+	if !slices.Equal(resolved, []string{"b"}) {
+		t.Errorf("Merge called resolve for %v, want only the shared key b", resolved)
+	}
+	if want := map[string]int{"b": 20, "c": 30}; !maps.Equal(*other, want) {
+		t.Errorf("Merge modified its argument: %v", *other)
+	}
+}
+
+func TestMapRemoveIf(t *testing.T) {
+	m := NewMap[int, string](0)
+	for i := range 10 {
+		m.Set(i, strings.Repeat("x", i%3))
+	}
+	removed := m.RemoveIf(func(k int, v string) bool { return k%2 == 0 || v == "" })
+	// even keys, plus the odd multiples of 3
+	if removed != 7 || m.Size() != 3 {
+		t.Errorf("RemoveIf removed %d entries, leaving %d; want 7, leaving 3", removed, m.Size())
+	}
+	if want := map[int]string{1: "x", 5: "xx", 7: "x"}; !maps.Equal(*m, want) {
+		t.Errorf("after RemoveIf: %v, want %v", *m, want)
+	}
+	if removed := m.RemoveIf(func(int, string) bool { return false }); removed != 0 {
+		t.Errorf("RemoveIf(false) removed %d entries", removed)
+	}
+}
+
+func TestMapFilterAndMapValues(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1, "bb": 2, "ccc": 3, "dd": 4})
+
+	keys := m.FilterKeys(func(k string) bool { return len(k) == 2 })
+	if want := map[string]int{"bb": 2, "dd": 4}; !maps.Equal(*keys, want) {
+		t.Errorf("FilterKeys = %v, want %v", *keys, want)
+	}
+	values := m.FilterValues(func(v int) bool { return v%2 == 1 })
+	if want := map[string]int{"a": 1, "ccc": 3}; !maps.Equal(*values, want) {
+		t.Errorf("FilterValues = %v, want %v", *values, want)
+	}
+	if m.Size() != 4 {
+		t.Errorf("filtering modified the original Map: %v", *m)
+	}
+
+	mapped := MapValues(m, func(k string, v int) string { return strings.Repeat(k[:1], v) })
+	want := map[string]string{"a": "a", "bb": "bb", "ccc": "ccc", "dd": "dddd"}
+	if !maps.Equal(*mapped, want) {
+		t.Errorf("MapValues = %v, want %v", *mapped, want)
+	}
+}
+
+func TestMapInvert(t *testing.T) {
+	m := AsMap(map[string]int{"a": 1, "b": 2, "c": 3})
+	if want := map[int]string{1: "a", 2: "b", 3: "c"}; !maps.Equal(*Invert(m), want) {
+		t.Errorf("Invert = %v, want %v", *Invert(m), want)
+	}
+
+	// with duplicate values, each value maps to one of its keys
+	m = AsMap(map[string]int{"a": 1, "b": 2, "c": 1, "d": 1})
+	inv := Invert(m)
+	if inv.Size() != 2 {
+		t.Errorf("Invert has %d entries, want one per distinct value (2)", inv.Size())
+	}
+	for v, k := range *inv {
+		if got := m.GetOrDefault(k, 0); got != v {
+			t.Errorf("Invert maps %d to %q, whose value is %d", v, k, got)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package collections
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelOptions configures the parallel List operations.
+// The zero value is ready to use, and selects sensible defaults.
+type ParallelOptions struct {
+	// Workers is the maximum number of goroutines used to process the List.
+	// If Workers <= 0, runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	// ChunkSize is the number of elements processed by a worker at a time.
+	// If ChunkSize <= 0, the List is split evenly between the workers.
+	ChunkSize int
+}
+
+// Parallel methods
+
+// ParallelSort sorts this List according to the provided less function,
+// using a merge sort spread over multiple goroutines. Like Sort, the sort is
+// stable.
+func (l *List[T]) ParallelSort(less func(s, t T) bool, opts ParallelOptions) {
+	bounds := opts.chunkBounds(l.Size())
+
+	// Sort each chunk independently.
+	opts.run(len(bounds)-1, func(c int) {
+		chunk := (*l)[bounds[c]:bounds[c+1]]
+		sort.SliceStable(chunk, func(i, j int) bool {
+			return less(chunk[i], chunk[j])
+		})
+	})
+
+	// Merge adjacent pairs of sorted runs until only one run remains,
+	// alternating between the List and a buffer.
+	src, dst := []T(*l), make([]T, l.Size())
+	for len(bounds) > 2 {
+		runs := len(bounds) - 1
+		opts.run((runs+1)/2, func(p int) {
+			lo, mid := bounds[2*p], bounds[2*p+1]
+			if 2*p+2 > runs {
+				// odd run out - carry it over to the next round
+				copy(dst[lo:mid], src[lo:mid])
+				return
+			}
+			hi := bounds[2*p+2]
+			merge(dst[lo:hi], src[lo:mid], src[mid:hi], less)
+		})
+
+		next := make([]int, 0, runs/2+2)
+		for i := 0; i < len(bounds); i += 2 {
+			next = append(next, bounds[i])
+		}
+		if runs%2 == 1 {
+			next = append(next, bounds[runs])
+		}
+		bounds = next
+		src, dst = dst, src
+	}
+
+	if l.Size() > 0 && &src[0] != &(*l)[0] {
+		copy(*l, src)
+	}
+}
+
+// ParallelFilter returns a new List containing only the elements t in this
+// List such that f(index(t), t) == true, evaluating f on multiple
+// goroutines. The order of elements is preserved.
+func (l *List[T]) ParallelFilter(f func(int, T) bool, opts ParallelOptions) *List[T] {
+	bounds := opts.chunkBounds(l.Size())
+	results := make([][]T, len(bounds)-1)
+	opts.run(len(results), func(c int) {
+		for i := bounds[c]; i < bounds[c+1]; i++ {
+			if f(i, (*l)[i]) {
+				results[c] = append(results[c], (*l)[i])
+			}
+		}
+	})
+
+	size := 0
+	for _, r := range results {
+		size += len(r)
+	}
+	fList := NewList[T](size)
+	for _, r := range results {
+		fList.Append(r...)
+	}
+	return fList
+}
+
+// ParallelCount counts the number of elements t in this List such that
+// f(index(t), t) == true, evaluating f on multiple goroutines.
+func (l *List[T]) ParallelCount(f func(int, T) bool, opts ParallelOptions) int {
+	bounds := opts.chunkBounds(l.Size())
+	var count atomic.Int64
+	opts.run(len(bounds)-1, func(c int) {
+		n := 0
+		for i := bounds[c]; i < bounds[c+1]; i++ {
+			if f(i, (*l)[i]) {
+				n++
+			}
+		}
+		count.Add(int64(n))
+	})
+	return int(count.Load())
+}
+
+// ParallelMap returns a new List containing f(index(t), t) for each element
+// t in the given List, evaluating f on multiple goroutines.
+func ParallelMap[T, U any](l *List[T], f func(int, T) U, opts ParallelOptions) *List[U] {
+	mapped := make(List[U], l.Size())
+	bounds := opts.chunkBounds(l.Size())
+	opts.run(len(bounds)-1, func(c int) {
+		for i := bounds[c]; i < bounds[c+1]; i++ {
+			mapped[i] = f(i, (*l)[i])
+		}
+	})
+	return &mapped
+}
+
+// Internal functions
+
+// workers returns the number of worker goroutines to use.
+func (o ParallelOptions) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// chunkBounds splits the range [0, n) into chunks, returning the boundaries
+// between chunks: chunk c is [bounds[c], bounds[c+1]).
+func (o ParallelOptions) chunkBounds(n int) []int {
+	size := o.ChunkSize
+	if size <= 0 {
+		workers := o.workers()
+		size = (n + workers - 1) / workers
+	}
+	if size <= 0 {
+		size = 1
+	}
+
+	bounds := make([]int, 0, n/size+2)
+	for lo := 0; lo < n; lo += size {
+		bounds = append(bounds, lo)
+	}
+	return append(bounds, n)
+}
+
+// run calls f(0), ..., f(tasks-1) using at most o.workers() goroutines,
+// and waits for all calls to return.
+func (o ParallelOptions) run(tasks int, f func(int)) {
+	workers := o.workers()
+	if workers > tasks {
+		workers = tasks
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				task := int(next.Add(1)) - 1
+				if task >= tasks {
+					return
+				}
+				f(task)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// merge merges the sorted slices a and b into dst, which must have length
+// len(a)+len(b). Ties are resolved in favour of a, so the merge is stable.
+func merge[T any](dst, a, b []T, less func(s, t T) bool) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if less(b[j], a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
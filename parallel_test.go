@@ -0,0 +1,136 @@
+package collections
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+type keyed struct {
+	key, seq int
+}
+
+func keyedLess(a, b keyed) bool {
+	return a.key < b.key
+}
+
+// randomKeyed returns n elements with many duplicate keys, each tagged with
+// its original position so stability can be checked.
+func randomKeyed(n int, seed uint64) *List[keyed] {
+	r := rand.New(seeded(seed))
+	l := NewList[keyed](n)
+	for i := 0; i < n; i++ {
+		l.Append(keyed{r.IntN(n/10 + 1), i})
+	}
+	return l
+}
+
+var parallelOptionCases = []ParallelOptions{
+	{},
+	{Workers: 1},
+	{Workers: 3},
+	{Workers: 4, ChunkSize: 1},
+	{Workers: 2, ChunkSize: 7},
+	{Workers: 8, ChunkSize: 100},
+}
+
+func TestParallelSortStable(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 100, 1001, 10000} {
+		for _, opts := range parallelOptionCases {
+			l := randomKeyed(n, uint64(n))
+			want := l.Copy()
+			want.Sort(keyedLess)
+
+			l.ParallelSort(keyedLess, opts)
+			if !slices.Equal(*l, *want) {
+				t.Errorf("n=%d, opts=%+v: ParallelSort differs from Sort", n, opts)
+			}
+		}
+	}
+}
+
+func TestParallelSortOddChunkCounts(t *testing.T) {
+	// Chunk counts of 3, 5 and 11 exercise carrying an odd run over to the
+	// next round of merging.
+	for _, chunks := range []int{3, 5, 11} {
+		n := chunks * 10
+		l := randomKeyed(n, 5)
+		want := l.Copy()
+		want.Sort(keyedLess)
+
+		l.ParallelSort(keyedLess, ParallelOptions{Workers: 2, ChunkSize: 10})
+		if !slices.Equal(*l, *want) {
+			t.Errorf("%d chunks: ParallelSort differs from Sort", chunks)
+		}
+	}
+}
+
+func TestParallelFilterCountMap(t *testing.T) {
+	even := func(i int, k keyed) bool { return (i+k.key)%2 == 0 }
+	double := func(i int, k keyed) int { return i + 2*k.key }
+
+	for _, n := range []int{0, 1, 13, 1000} {
+		l := randomKeyed(n, 11)
+		wantFilter := l.Filter(even)
+		wantCount := l.Count(even)
+		wantMap := make([]int, n)
+		for i, k := range *l {
+			wantMap[i] = double(i, k)
+		}
+
+		for _, opts := range parallelOptionCases {
+			if got := l.ParallelFilter(even, opts); !slices.Equal(*got, *wantFilter) {
+				t.Errorf("n=%d, opts=%+v: ParallelFilter differs from Filter", n, opts)
+			}
+			if got := l.ParallelCount(even, opts); got != wantCount {
+				t.Errorf("n=%d, opts=%+v: ParallelCount = %d, want %d", n, opts, got, wantCount)
+			}
+			if got := ParallelMap(l, double, opts); !slices.Equal(*got, wantMap) {
+				t.Errorf("n=%d, opts=%+v: ParallelMap differs from sequential map", n, opts)
+			}
+		}
+	}
+}
+
+func TestSortUnstable(t *testing.T) {
+	l := randomKeyed(1000, 3)
+	l.SortUnstable(keyedLess)
+	if !slices.IsSortedFunc(*l, func(a, b keyed) int { return a.key - b.key }) {
+		t.Errorf("SortUnstable did not sort the List")
+	}
+}
+
+var sortBenchmarkSizes = []int{1e3, 1e5, 1e6}
+
+func benchmarkSort(b *testing.B, sortFunc func(l *List[int])) {
+	for _, n := range sortBenchmarkSizes {
+		r := rand.New(seeded(1))
+		elems := make([]int, n)
+		for i := range elems {
+			elems[i] = r.Int()
+		}
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			l := NewList[int](n)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				*l = append((*l)[:0], elems...)
+				b.StartTimer()
+				sortFunc(l)
+			}
+		})
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	benchmarkSort(b, func(l *List[int]) { l.Sort(intLess) })
+}
+
+func BenchmarkParallelSort(b *testing.B) {
+	benchmarkSort(b, func(l *List[int]) { l.ParallelSort(intLess, ParallelOptions{}) })
+}
+
+func BenchmarkSortUnstable(b *testing.B) {
+	benchmarkSort(b, func(l *List[int]) { l.SortUnstable(intLess) })
+}
@@ -52,6 +52,38 @@ func (q *Queue[T]) Peek() (t T, err error) {
 	return
 }
 
+// PeekBack returns the back element of this Queue, without removing it
+// from the Queue. It returns an error if the Queue is empty.
+func (q *Queue[T]) PeekBack() (t T, err error) {
+	if q.IsEmpty() {
+		err = errQueueEmpty
+	} else {
+		t = q.peekBack()
+	}
+	return
+}
+
+// PeekN returns the front n elements of this Queue, from front to back,
+// without removing them from the Queue. It returns an error if n is negative
+// or greater than the size of the Queue.
+func (q *Queue[T]) PeekN(n int) (*List[T], error) {
+	if err := checkBatchSize("Queue", n, q.Size()); err != nil {
+		return nil, err
+	}
+	return copyBatch(q.elems[:n], false), nil
+}
+
+// ContainsFunc returns true if the given element is in the Queue, using eq
+// to compare elements for equality.
+func (q *Queue[T]) ContainsFunc(t T, eq func(s, t T) bool) bool {
+	for _, s := range q.elems {
+		if eq(s, t) {
+			return true
+		}
+	}
+	return false
+}
+
 // Basic (mutating) functions
 
 // Enqueue adds the given element to the back of this Queue.
@@ -66,19 +98,112 @@ func (q *Queue[T]) Dequeue() (t T, err error) {
 		err = errQueueEmpty
 	} else {
 		t = q.elems[0]
-		q.elems = q.elems[1:]
+		q.discardFront(1)
 	}
 	return
 }
 
+// EnqueueAll adds the given elements to the back of this Queue, in order.
+func (q *Queue[T]) EnqueueAll(ts ...T) {
+	q.elems = append(q.elems, ts...)
+}
+
+// DequeueN removes the front n elements of this Queue and returns them, from
+// front to back. It returns an error if n is negative or greater than the
+// size of the Queue, in which case the Queue is unchanged.
+func (q *Queue[T]) DequeueN(n int) (*List[T], error) {
+	dequeued, err := q.PeekN(n)
+	if err != nil {
+		return nil, err
+	}
+	q.discardFront(n)
+	return dequeued, nil
+}
+
+// RemoveIf removes all elements of this Queue for which f returns true,
+// preserving the order of the remaining elements. It returns the number of
+// elements removed.
+func (q *Queue[T]) RemoveIf(f func(t T) bool) int {
+	kept := 0
+	for _, t := range q.elems {
+		if !f(t) {
+			q.elems[kept] = t
+			kept++
+		}
+	}
+	removed := len(q.elems) - kept
+	clear(q.elems[kept:])
+	q.elems = q.elems[:kept]
+	return removed
+}
+
+// DrainTo removes every element of this Queue, from front to back, passing
+// each one to add. For example, q.DrainTo(s.Push) moves the elements of q
+// onto the Stack s. It returns the number of elements drained.
+func (q *Queue[T]) DrainTo(add func(t T)) int {
+	elems := q.elems
+	q.Clear()
+	for _, t := range elems {
+		add(t)
+	}
+	return len(elems)
+}
+
+// Clear removes all elements from this Queue, releasing its storage.
+func (q *Queue[T]) Clear() {
+	q.elems = nil
+}
+
 // Copying functions
 
 // Copy returns a copy of the given Queue.
 func (q *Queue[T]) Copy() *Queue[T] {
-	elemsCp := make([]T, 0, q.Size())
+	elemsCp := make([]T, q.Size())
 	copy(elemsCp, q.elems)
 	return &Queue[T]{elemsCp}
 }
 
+// CopyDeep returns a deep copy of the given Queue, in which each element is
+// copied using clone. If clone is nil, elements implementing Cloner[T] are
+// copied using their Clone method, and other elements are copied as-is.
+func (q *Queue[T]) CopyDeep(clone func(t T) T) *Queue[T] {
+	clone = cloneFunc(clone)
+	elemsCp := make([]T, q.Size())
+	for i, t := range q.elems {
+		elemsCp[i] = clone(t)
+	}
+	return &Queue[T]{elemsCp}
+}
+
+// Internal methods
+
+// peekBack returns the back element of this Queue. The Queue must be
+// non-empty.
+func (q *Queue[T]) peekBack() T {
+	return q.elems[len(q.elems)-1]
+}
+
+// discardFront removes the front n elements of this Queue, zeroing their
+// slots so they can be garbage collected.
+func (q *Queue[T]) discardFront(n int) {
+	clear(q.elems[:n])
+	q.elems = q.elems[n:]
+}
+
+// dequeueBack removes the back element of this Queue. The Queue must be
+// non-empty.
+func (q *Queue[T]) dequeueBack() {
+	var zero T
+	q.elems[len(q.elems)-1] = zero
+	q.elems = q.elems[:len(q.elems)-1]
+}
+
+// Functions on Queues of comparable elements
+
+// QueueContains returns true if the given element is in the Queue.
+func QueueContains[T comparable](q *Queue[T], t T) bool {
+	return q.ContainsFunc(t, equal[T])
+}
+
 // Errors
 var errQueueEmpty = fmt.Errorf("queue is empty")
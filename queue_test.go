@@ -0,0 +1,139 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestQueueAgainstSlice(t *testing.T) {
+	r := rand.New(seeded(48))
+	q := NewQueue[int](0)
+	var ref []int
+	for i := 0; i < 3000; i++ {
+		switch r.IntN(6) {
+		case 0, 1:
+			q.Enqueue(i)
+			ref = append(ref, i)
+		case 2:
+			n := r.IntN(4)
+			batch := []int{i, i + 1, i + 2}[:n]
+			q.EnqueueAll(batch...)
+			ref = append(ref, batch...)
+		case 3:
+			v, err := q.Dequeue()
+			if len(ref) == 0 {
+				if err == nil {
+					t.Fatalf("Dequeue() on an empty Queue returned %d", v)
+				}
+				break
+			}
+			if err != nil || v != ref[0] {
+				t.Fatalf("Dequeue() = %d, %v; want %d", v, err, ref[0])
+			}
+			ref = ref[1:]
+		case 4:
+			n := r.IntN(5)
+			got, err := q.DequeueN(n)
+			if n > len(ref) {
+				if err == nil || q.Size() != len(ref) {
+					t.Fatalf("DequeueN(%d) of %d should fail and leave the Queue unchanged", n, len(ref))
+				}
+				break
+			}
+			if err != nil || !slices.Equal(*got, ref[:n]) {
+				t.Fatalf("DequeueN(%d) = %v, %v; want %v", n, got, err, ref[:n])
+			}
+			ref = ref[n:]
+		case 5:
+			mod := 2 + r.IntN(5)
+			f := func(x int) bool { return x%mod == 0 }
+			kept := slices.DeleteFunc(slices.Clone(ref), f)
+			if n := q.RemoveIf(f); n != len(ref)-len(kept) {
+				t.Fatalf("RemoveIf removed %d, want %d", n, len(ref)-len(kept))
+			}
+			ref = kept
+		}
+
+		if !slices.Equal(q.AsSlice(), ref) {
+			t.Fatalf("Queue = %v, want %v", q.AsSlice(), ref)
+		}
+		if len(ref) > 0 {
+			front, _ := q.Peek()
+			back, _ := q.PeekBack()
+			if front != ref[0] || back != ref[len(ref)-1] {
+				t.Fatalf("Peek() = %d, PeekBack() = %d; want %d, %d", front, back, ref[0], ref[len(ref)-1])
+			}
+		} else if _, err := q.PeekBack(); err == nil {
+			t.Fatalf("expected error from PeekBack on an empty Queue")
+		}
+	}
+}
+
+func TestQueuePeekN(t *testing.T) {
+	q := AsQueue([]string{"a", "b", "c"})
+	got, err := q.PeekN(2)
+	if err != nil || !slices.Equal(*got, []string{"a", "b"}) || q.Size() != 3 {
+		t.Errorf("PeekN(2) = %v, %v", got, err)
+	}
+	got.Set(0, "z")
+	if front, _ := q.Peek(); front != "a" {
+		t.Errorf("modifying the result of PeekN changed the Queue")
+	}
+	for _, n := range []int{-1, 4} {
+		if _, err := q.PeekN(n); err == nil {
+			t.Errorf("expected error from PeekN(%d)", n)
+		}
+	}
+	if !QueueContains(q, "c") || QueueContains(q, "z") {
+		t.Errorf("QueueContains disagrees with the contents")
+	}
+	if !q.ContainsFunc("B", func(s, t string) bool { return s == "b" && t == "B" }) {
+		t.Errorf("ContainsFunc did not use eq")
+	}
+}
+
+func TestQueueZeroesRemovedSlots(t *testing.T) {
+	q := NewQueue[*int](0)
+	for i := 0; i < 8; i++ {
+		q.Enqueue(new(int))
+	}
+	backing := q.AsSlice()[:8]
+	q.Dequeue()
+	q.DequeueN(2)
+	for i, p := range backing[:3] {
+		if p != nil {
+			t.Errorf("slot %d still holds a dequeued pointer", i)
+		}
+	}
+
+	q.RemoveIf(func(p *int) bool { return p == backing[4] })
+	if backing[7] != nil {
+		t.Errorf("RemoveIf left a pointer in the vacated slot")
+	}
+}
+
+func TestQueueDrainTo(t *testing.T) {
+	q := AsQueue([]int{1, 2, 3})
+	s := NewStack[int](0)
+	if n := q.DrainTo(s.Push); n != 3 {
+		t.Errorf("DrainTo returned %d, want 3", n)
+	}
+	if !q.IsEmpty() || !slices.Equal(s.AsSlice(), []int{1, 2, 3}) {
+		t.Errorf("after DrainTo: Queue %v, Stack %v", q.AsSlice(), s.AsSlice())
+	}
+
+	// Elements re-enqueued during draining stay in the Queue.
+	q = AsQueue([]int{1, 2, 3})
+	if n := q.DrainTo(func(x int) { q.Enqueue(10 * x) }); n != 3 {
+		t.Errorf("DrainTo returned %d, want 3", n)
+	}
+	if !slices.Equal(q.AsSlice(), []int{10, 20, 30}) {
+		t.Errorf("after re-entrant DrainTo: %v", q.AsSlice())
+	}
+
+	q.Clear()
+	if !q.IsEmpty() || q.Capacity() != 0 {
+		t.Errorf("Clear should release the storage; capacity %d", q.Capacity())
+	}
+}
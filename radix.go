@@ -0,0 +1,208 @@
+package collections
+
+// RadixTree is an implementation of a map from strings to values using a
+// compressed prefix tree. It has the same API as Trie, but each node may
+// represent several bytes, so that chains of nodes with a single child are
+// merged. This uses much less memory when keys share long prefixes or have
+// long unique suffixes.
+type RadixTree[V any] struct {
+	root trieNode[V]
+}
+
+// Constructors
+
+// NewRadixTree makes a new empty RadixTree.
+func NewRadixTree[V any]() *RadixTree[V] {
+	return &RadixTree[V]{}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of keys in this RadixTree.
+func (t *RadixTree[V]) Size() int {
+	return t.root.count
+}
+
+// IsEmpty returns true if this RadixTree is empty.
+func (t *RadixTree[V]) IsEmpty() bool {
+	return t.Size() == 0
+}
+
+// Contains returns true if the given key is in the RadixTree.
+func (t *RadixTree[V]) Contains(key string) bool {
+	n := t.root.find(key)
+	return n != nil && n.hasValue
+}
+
+// Get returns the value associated with key. If key is not in this
+// RadixTree, Get returns an error.
+func (t *RadixTree[V]) Get(key string) (V, error) {
+	return t.root.get(key)
+}
+
+// LongestPrefixMatch returns the longest key in this RadixTree which is a
+// prefix of s, along with its value. It returns an error if no key is a
+// prefix of s.
+func (t *RadixTree[V]) LongestPrefixMatch(s string) (string, V, error) {
+	return t.root.longestPrefixMatch(s)
+}
+
+// CountPrefix returns the number of keys in this RadixTree which start with
+// the given prefix.
+func (t *RadixTree[V]) CountPrefix(prefix string) int {
+	n, _ := t.root.findPrefix(prefix)
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
+
+// KeysWithPrefix returns an Iterator over the keys in this RadixTree which
+// start with the given prefix, in lexicographic order. The keys are found
+// lazily, so the RadixTree must not be modified during iteration.
+func (t *RadixTree[V]) KeysWithPrefix(prefix string) Iterator[string] {
+	return &trieKeyIterator[V]{newTrieIterator(&t.root, prefix)}
+}
+
+// IterateWithPrefix returns an Iterator2 over the keys in this RadixTree
+// which start with the given prefix and their values, in lexicographic order
+// of the keys. The entries are found lazily, so the RadixTree must not be
+// modified during iteration.
+func (t *RadixTree[V]) IterateWithPrefix(prefix string) Iterator2[string, V] {
+	return newTrieIterator(&t.root, prefix)
+}
+
+// Basic (mutating) functions
+
+// Insert associates v with key in this RadixTree. If there is already a
+// value associated with key, it will be overwritten.
+func (t *RadixTree[V]) Insert(key string, v V) {
+	if n := t.root.find(key); n != nil && n.hasValue {
+		n.value = v
+		return
+	}
+
+	n := &t.root
+	n.count++
+	rest := key
+	for rest != "" {
+		j, ok := n.child(rest[0])
+		if !ok {
+			n.insertChild(j, &trieNode[V]{label: rest, value: v, hasValue: true, count: 1})
+			return
+		}
+
+		c := n.children[j]
+		common := commonPrefixLen(rest, c.label)
+		if common < len(c.label) {
+			// split the edge to c
+			mid := &trieNode[V]{
+				label:    c.label[:common],
+				children: []*trieNode[V]{c},
+				count:    c.count,
+			}
+			c.label = c.label[common:]
+			n.children[j] = mid
+			c = mid
+		}
+		c.count++
+		n = c
+		rest = rest[common:]
+	}
+	n.value, n.hasValue = v, true
+}
+
+// Delete removes key and its associated value from this RadixTree. It
+// returns false if key was not in the RadixTree to begin with, and returns
+// true if key was removed.
+func (t *RadixTree[V]) Delete(key string) bool {
+	if !t.Contains(key) {
+		return false
+	}
+
+	var parent *trieNode[V]
+	parentIndex := 0
+	n := &t.root
+	n.count--
+	for rest := key; rest != ""; {
+		j, _ := n.child(rest[0])
+		parent, parentIndex = n, j
+		n = n.children[j]
+		n.count--
+		rest = rest[len(n.label):]
+	}
+	var z V
+	n.value, n.hasValue = z, false
+
+	switch {
+	case n.count == 0 && parent != nil:
+		parent.removeChild(parentIndex)
+		// parent may now be mergeable with its remaining child
+		if parent != &t.root {
+			parent.mergeChild()
+		}
+	case n != &t.root:
+		n.mergeChild()
+	}
+	return true
+}
+
+// Clear removes all keys from this RadixTree.
+func (t *RadixTree[V]) Clear() {
+	t.root = trieNode[V]{}
+}
+
+// Helpers for String
+
+// InsertString associates v with key in this RadixTree.
+func (t *RadixTree[V]) InsertString(key String, v V) {
+	t.Insert(string(key), v)
+}
+
+// GetString returns the value associated with key, or an error if key is not
+// in this RadixTree.
+func (t *RadixTree[V]) GetString(key String) (V, error) {
+	return t.Get(string(key))
+}
+
+// DeleteString removes key and its associated value from this RadixTree, and
+// returns true if key was removed.
+func (t *RadixTree[V]) DeleteString(key String) bool {
+	return t.Delete(string(key))
+}
+
+// LongestPrefixMatchString returns the longest key in this RadixTree which is
+// a prefix of s, along with its value, or an error if there is no such key.
+func (t *RadixTree[V]) LongestPrefixMatchString(s String) (String, V, error) {
+	key, v, err := t.LongestPrefixMatch(string(s))
+	return String(key), v, err
+}
+
+// KeysWithPrefixString returns an Iterator over the keys in this RadixTree
+// which start with the given prefix, in lexicographic order.
+func (t *RadixTree[V]) KeysWithPrefixString(prefix String) Iterator[String] {
+	return &trieStringIterator{t.KeysWithPrefix(string(prefix))}
+}
+
+// Internal functions
+
+// mergeChild merges n with its only child, if n has no value and exactly
+// one child.
+func (n *trieNode[V]) mergeChild() {
+	if n.hasValue || len(n.children) != 1 {
+		return
+	}
+	c := n.children[0]
+	n.label += c.label
+	n.children = c.children
+	n.value, n.hasValue = c.value, c.hasValue
+}
+
+// commonPrefixLen returns the length of the longest common prefix of s and t.
+func commonPrefixLen(s, t string) int {
+	i := 0
+	for i < len(s) && i < len(t) && s[i] == t[i] {
+		i++
+	}
+	return i
+}
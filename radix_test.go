@@ -0,0 +1,110 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRadixTreeAgainstMap(t *testing.T) {
+	rt := NewRadixTree[int]()
+	testPrefixMapAgainstMap(t, rt, func() {
+		checkTrieNode(t, &rt.root, true, true)
+	})
+}
+
+// radixLabels returns the labels of the children of n, in order.
+func radixLabels[V any](n *trieNode[V]) []string {
+	var labels []string
+	for _, c := range n.children {
+		labels = append(labels, c.label)
+	}
+	return labels
+}
+
+func TestRadixTreeSplitAndMerge(t *testing.T) {
+	rt := NewRadixTree[int]()
+	rt.Insert("romane", 1)
+	if got := radixLabels(&rt.root); !slices.Equal(got, []string{"romane"}) {
+		t.Fatalf("root children = %q", got)
+	}
+
+	// Inserting a key which diverges part way along an edge splits it.
+	rt.Insert("romulus", 2)
+	if got := radixLabels(&rt.root); !slices.Equal(got, []string{"rom"}) {
+		t.Fatalf("root children after split = %q", got)
+	}
+	rom := rt.root.children[0]
+	if got := radixLabels(rom); !slices.Equal(got, []string{"ane", "ulus"}) {
+		t.Fatalf("children of \"rom\" = %q", got)
+	}
+	if rom.hasValue {
+		t.Errorf("split node has a value")
+	}
+
+	// Inserting a key which ends part way along an edge also splits it.
+	rt.Insert("ro", 3)
+	ro := rt.root.children[0]
+	if ro.label != "ro" || !ro.hasValue || !slices.Equal(radixLabels(ro), []string{"m"}) {
+		t.Fatalf("after inserting \"ro\": label %q, children %q", ro.label, radixLabels(ro))
+	}
+
+	// Deleting "ro" leaves a node with one child, which is merged.
+	rt.Delete("ro")
+	if got := radixLabels(&rt.root); !slices.Equal(got, []string{"rom"}) {
+		t.Fatalf("root children after deleting \"ro\" = %q", got)
+	}
+
+	// Deleting "romane" leaves "rom" with one child, which is merged.
+	rt.Delete("romane")
+	if got := radixLabels(&rt.root); !slices.Equal(got, []string{"romulus"}) {
+		t.Fatalf("root children after deleting \"romane\" = %q", got)
+	}
+	if v, err := rt.Get("romulus"); err != nil || v != 2 {
+		t.Errorf("Get(\"romulus\") = %d, %v", v, err)
+	}
+
+	rt.Delete("romulus")
+	if !rt.IsEmpty() || len(rt.root.children) != 0 {
+		t.Errorf("tree not empty after deleting every key")
+	}
+}
+
+func TestRadixTreePrefixWithinEdge(t *testing.T) {
+	rt := NewRadixTree[int]()
+	rt.Insert("abcdef", 1)
+	rt.Insert("abcxyz", 2)
+	if got := rt.CountPrefix("ab"); got != 2 {
+		t.Errorf("CountPrefix(\"ab\") = %d, want 2", got)
+	}
+	if got := rt.CountPrefix("abcd"); got != 1 {
+		t.Errorf("CountPrefix(\"abcd\") = %d, want 1", got)
+	}
+	if got := rt.CountPrefix("abd"); got != 0 {
+		t.Errorf("CountPrefix(\"abd\") = %d, want 0", got)
+	}
+	if _, _, err := rt.LongestPrefixMatch("abcde"); err == nil {
+		t.Errorf("expected error: no key is a prefix of \"abcde\"")
+	}
+}
+
+func TestRadixTreeStringHelpers(t *testing.T) {
+	rt := NewRadixTree[int]()
+	rt.InsertString("test", 1)
+	rt.InsertString("team", 2)
+	if v, err := rt.GetString("team"); err != nil || v != 2 {
+		t.Errorf("GetString(\"team\") = %d, %v", v, err)
+	}
+	if key, v, err := rt.LongestPrefixMatchString("testing"); err != nil || key != "test" || v != 1 {
+		t.Errorf("LongestPrefixMatchString(\"testing\") = %q, %d, %v", key, v, err)
+	}
+	var keys []String
+	for it := rt.KeysWithPrefixString("te"); it.HasNext(); {
+		keys = append(keys, it.Next())
+	}
+	if !slices.Equal(keys, []String{"team", "test"}) {
+		t.Errorf("KeysWithPrefixString(\"te\") = %q", keys)
+	}
+	if !rt.DeleteString("test") || rt.DeleteString("test") {
+		t.Errorf("DeleteString did not report presence correctly")
+	}
+}
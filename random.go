@@ -0,0 +1,83 @@
+package collections
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Methods which make random choices take a rand.Source from math/rand/v2, so
+// that the results can be made reproducible by passing a seeded source, e.g.
+//
+//	l.ShuffleWith(rand.NewPCG(1, 2))
+//
+// If the source is nil, the global random source is used.
+
+// globalSource is a rand.Source which draws from the global random source.
+type globalSource struct{}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// randFrom returns a rand.Rand drawing from the given source, or from the
+// global source if src is nil.
+func randFrom(src rand.Source) *rand.Rand {
+	if src == nil {
+		src = globalSource{}
+	}
+	return rand.New(src)
+}
+
+// SampleIterator returns a List containing k elements chosen uniformly at
+// random, without replacement, from the values of the given Iterator. It
+// consumes the whole Iterator, and uses reservoir sampling so that only k
+// elements are held in memory at a time. If the Iterator has fewer than k
+// values, all of them are returned.
+func SampleIterator[T any](it Iterator[T], k int, src rand.Source) (*List[T], error) {
+	if k < 0 {
+		return nil, errNegativeSampleSize(k)
+	}
+
+	r := randFrom(src)
+	sample := NewList[T](k)
+	for n := 0; it.HasNext(); n++ {
+		t := it.Next()
+		if n < k {
+			sample.Append(t)
+		} else if j := r.IntN(n + 1); j < k {
+			(*sample)[j] = t
+		}
+	}
+	return sample, nil
+}
+
+// weightedKeys returns, for each element of l, a random key such that
+// choosing the elements with the k largest keys is a weighted sample without
+// replacement (Efraimidis-Spirakis). Elements with non-positive weight get
+// key -Inf, and the number of elements with positive weight is returned.
+func weightedKeys[T any](l *List[T], weight func(int, T) float64, r *rand.Rand) ([]float64, int) {
+	keys := make([]float64, l.Size())
+	positive := 0
+	for i, t := range *l {
+		w := weight(i, t)
+		if w <= 0 {
+			keys[i] = math.Inf(-1)
+			continue
+		}
+		positive++
+		// Use log(u)/w rather than u^(1/w) to avoid underflow.
+		keys[i] = math.Log(1-r.Float64()) / w
+	}
+	return keys, positive
+}
+
+// Errors
+
+func errNegativeSampleSize(k int) error {
+	return fmt.Errorf("sample size %d is negative", k)
+}
+
+func errSampleTooLarge(k, n int) error {
+	return fmt.Errorf("sample size %d larger than number of candidates %d", k, n)
+}
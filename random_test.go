@@ -0,0 +1,220 @@
+package collections
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func seeded(seed uint64) rand.Source {
+	return rand.NewPCG(seed, seed)
+}
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func TestShuffleWithReproducible(t *testing.T) {
+	shuffled := func(seed uint64) []int {
+		l := NewList[int](100)
+		for i := 0; i < 100; i++ {
+			l.Append(i)
+		}
+		l.ShuffleWith(seeded(seed))
+		return *l
+	}
+
+	a, b := shuffled(1), shuffled(1)
+	if !slices.Equal(a, b) {
+		t.Fatalf("same seed gave different shuffles:\n%v\n%v", a, b)
+	}
+	if slices.Equal(a, shuffled(2)) {
+		t.Errorf("different seeds gave the same shuffle")
+	}
+	sorted := slices.Clone(a)
+	slices.Sort(sorted)
+	for i, n := range sorted {
+		if n != i {
+			t.Fatalf("shuffle is not a permutation: %v", a)
+		}
+	}
+}
+
+func TestSampleWithoutReplacement(t *testing.T) {
+	l := AsList([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	for seed := uint64(0); seed < 50; seed++ {
+		s1, err := l.Sample(5, seeded(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s2, _ := l.Sample(5, seeded(seed))
+		if !slices.Equal(*s1, *s2) {
+			t.Fatalf("seed %d: samples differ: %v, %v", seed, *s1, *s2)
+		}
+		if AsSet(*s1).Size() != 5 {
+			t.Fatalf("seed %d: sample has repeated elements: %v", seed, *s1)
+		}
+	}
+	if _, err := l.Sample(11, nil); err == nil {
+		t.Errorf("expected error for sample larger than List")
+	}
+	if _, err := l.Sample(-1, nil); err == nil {
+		t.Errorf("expected error for negative sample size")
+	}
+}
+
+// sliceIterator is an Iterator over the elements of a slice.
+type sliceIterator[T any] struct {
+	elems []T
+}
+
+func (i *sliceIterator[T]) HasNext() bool {
+	return len(i.elems) > 0
+}
+
+func (i *sliceIterator[T]) Next() T {
+	t := i.elems[0]
+	i.elems = i.elems[1:]
+	return t
+}
+
+func TestSampleIteratorUniform(t *testing.T) {
+	const n, k, trials = 10, 3, 20000
+	l := NewList[int](n)
+	for i := 0; i < n; i++ {
+		l.Append(i)
+	}
+
+	counts := make([]int, n)
+	src := seeded(42)
+	for i := 0; i < trials; i++ {
+		sample, err := SampleIterator[int](&sliceIterator[int]{*l}, k, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if AsSet(*sample).Size() != k {
+			t.Fatalf("sample has repeated elements: %v", *sample)
+		}
+		for _, x := range *sample {
+			counts[x]++
+		}
+	}
+	want := float64(trials * k / n)
+	for x, c := range counts {
+		if math.Abs(float64(c)-want) > 0.05*want {
+			t.Errorf("element %d chosen %d times, want about %.0f", x, c, want)
+		}
+	}
+
+	short, _ := SampleIterator[int](&sliceIterator[int]{[]int{1, 2}}, 5, nil)
+	if short.Size() != 2 {
+		t.Errorf("sample from short iterator = %v, want both elements", *short)
+	}
+}
+
+func TestWeightedRandomElement(t *testing.T) {
+	l := AsList([]string{"never", "once", "thrice"})
+	weight := func(i int, _ string) float64 { return []float64{0, 1, 3}[i] }
+
+	counts := map[string]int{}
+	src := seeded(7)
+	for i := 0; i < 40000; i++ {
+		s, err := l.WeightedRandomElement(weight, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[s]++
+	}
+	if counts["never"] != 0 {
+		t.Errorf("zero-weight element chosen %d times", counts["never"])
+	}
+	if ratio := float64(counts["thrice"]) / float64(counts["once"]); math.Abs(ratio-3) > 0.15 {
+		t.Errorf("weight ratio %.2f, want about 3", ratio)
+	}
+
+	zero := func(int, string) float64 { return 0 }
+	if _, err := l.WeightedRandomElement(zero, nil); err == nil {
+		t.Errorf("expected error when no element has positive weight")
+	}
+}
+
+func TestWeightedSample(t *testing.T) {
+	l := AsList([]int{0, 1, 2, 3, 4})
+	weight := func(_ int, x int) float64 { return float64(x) }
+
+	s1, err := l.WeightedSample(4, weight, seeded(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, _ := l.WeightedSample(4, weight, seeded(3))
+	if !slices.Equal(*s1, *s2) {
+		t.Errorf("samples differ for the same seed: %v, %v", *s1, *s2)
+	}
+	if ListContains(s1, 0) || AsSet(*s1).Size() != 4 {
+		t.Errorf("sample %v should be the 4 positive-weight elements", *s1)
+	}
+	if _, err := l.WeightedSample(5, weight, nil); err == nil {
+		t.Errorf("expected error when k exceeds the positive-weight elements")
+	}
+}
+
+func TestSetRandomElementOrderedReproducible(t *testing.T) {
+	// Build equal Sets in different insertion orders, so their map layouts
+	// are likely to differ.
+	forward, backward := NewSet[int](0), NewSet[int](0)
+	for i := 0; i < 100; i++ {
+		forward.Add(i)
+		backward.Add(99 - i)
+	}
+
+	for seed := uint64(0); seed < 20; seed++ {
+		a, err := forward.RandomElementOrdered(intLess, seeded(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := backward.RandomElementOrdered(intLess, seeded(seed))
+		if a != b {
+			t.Fatalf("seed %d: got %d and %d from equal Sets", seed, a, b)
+		}
+	}
+
+	f, b := forward.Copy(), backward.Copy()
+	srcF, srcB := seeded(9), seeded(9)
+	for !f.IsEmpty() {
+		x, _ := f.PopOrdered(intLess, srcF)
+		y, _ := b.PopOrdered(intLess, srcB)
+		if x != y || f.Contains(x) {
+			t.Fatalf("PopOrdered gave %d and %d", x, y)
+		}
+	}
+	if _, err := f.PopOrdered(intLess, nil); err == nil {
+		t.Errorf("expected error popping from an empty Set")
+	}
+}
+
+func TestSetRandomElement(t *testing.T) {
+	s := NewSet[int](0)
+	if _, err := s.RandomElement(nil); err == nil {
+		t.Errorf("expected error from an empty Set")
+	}
+	s = AsSet([]int{1, 2, 3})
+	if x, err := s.RandomElement(seeded(1)); err != nil || !s.Contains(x) {
+		t.Errorf("RandomElement(src) = %d, %v; want an element of %v", x, err, s.Slice())
+	}
+	for i := 0; !s.IsEmpty(); i++ {
+		size := s.Size()
+		// a seeded source and nil (the global source) are both accepted
+		var src rand.Source
+		if i%2 == 0 {
+			src = seeded(uint64(i))
+		}
+		x, err := s.Pop(src)
+		if err != nil || s.Contains(x) || s.Size() != size-1 {
+			t.Fatalf("Pop() = %d, %v leaving %v", x, err, s.Slice())
+		}
+	}
+	if _, err := s.Pop(nil); err == nil {
+		t.Errorf("expected error from Pop on an empty Set")
+	}
+}
@@ -0,0 +1,181 @@
+package collections
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// RangeMap is a map from half-open intervals of keys to values. Each key is
+// associated with at most one value: setting the value of a range overwrites
+// the values of any overlapping ranges. Adjacent ranges with equal values are
+// coalesced into a single range.
+//
+// Keys are ordered by the less function given to NewRangeMap, in the same way
+// as Map.Iterate orders keys.
+type RangeMap[K any, V comparable] struct {
+	// entries are sorted, disjoint and non-empty, and adjacent entries have
+	// different values.
+	entries []Entry[Interval[K], V]
+	less    func(K, K) bool
+}
+
+// Constructors
+
+// NewRangeMap makes a new empty RangeMap, using less to order keys.
+func NewRangeMap[K any, V comparable](less func(K, K) bool) *RangeMap[K, V] {
+	return &RangeMap[K, V]{less: less}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of (coalesced) ranges in this RangeMap.
+func (m *RangeMap[K, V]) Size() int {
+	return len(m.entries)
+}
+
+// IsEmpty returns true if this RangeMap is empty.
+func (m *RangeMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Get returns the value associated with k. If k is not in any range in this
+// RangeMap, Get returns an error.
+func (m *RangeMap[K, V]) Get(k K) (v V, err error) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.less(k, m.entries[i].Key.High)
+	})
+	if i == len(m.entries) || m.less(k, m.entries[i].Key.Low) {
+		err = errKeyNotInRange(k)
+		return
+	}
+	return m.entries[i].Value, nil
+}
+
+// Contains returns true if k is in some range in this RangeMap.
+func (m *RangeMap[K, V]) Contains(k K) bool {
+	_, err := m.Get(k)
+	return err == nil
+}
+
+// Basic (mutating) functions
+
+// Set associates every key in the range iv with v, overwriting the values of
+// any keys in iv which were already in the RangeMap.
+// It returns an error if iv is empty, i.e. iv.Low is not less than iv.High.
+func (m *RangeMap[K, V]) Set(iv Interval[K], v V) error {
+	if !m.less(iv.Low, iv.High) {
+		return errEmptyInterval(iv)
+	}
+
+	// Replace the overlapping entries with the parts lying outside iv, and
+	// the new entry.
+	lo, hi := m.overlapping(iv)
+	replacement := make([]Entry[Interval[K], V], 0, 3)
+	if lo < hi && m.less(m.entries[lo].Key.Low, iv.Low) {
+		replacement = append(replacement, m.entry(m.entries[lo].Key.Low, iv.Low, m.entries[lo].Value))
+	}
+	i := lo + len(replacement)
+	replacement = append(replacement, m.entry(iv.Low, iv.High, v))
+	if lo < hi && m.less(iv.High, m.entries[hi-1].Key.High) {
+		replacement = append(replacement, m.entry(iv.High, m.entries[hi-1].Key.High, m.entries[hi-1].Value))
+	}
+	m.entries = slices.Replace(m.entries, lo, hi, replacement...)
+
+	// Coalesce with neighbours
+	m.coalesce(i)
+	m.coalesce(i - 1)
+	return nil
+}
+
+// Remove removes every key in the range iv from this RangeMap. It returns
+// true if any keys were removed.
+func (m *RangeMap[K, V]) Remove(iv Interval[K]) bool {
+	if !m.less(iv.Low, iv.High) {
+		return false
+	}
+
+	lo, hi := m.overlapping(iv)
+	if lo == hi {
+		return false
+	}
+	replacement := make([]Entry[Interval[K], V], 0, 2)
+	if m.less(m.entries[lo].Key.Low, iv.Low) {
+		replacement = append(replacement, m.entry(m.entries[lo].Key.Low, iv.Low, m.entries[lo].Value))
+	}
+	if m.less(iv.High, m.entries[hi-1].Key.High) {
+		replacement = append(replacement, m.entry(iv.High, m.entries[hi-1].Key.High, m.entries[hi-1].Value))
+	}
+	m.entries = slices.Replace(m.entries, lo, hi, replacement...)
+	return true
+}
+
+// Clear removes all ranges from this RangeMap.
+func (m *RangeMap[K, V]) Clear() {
+	m.entries = nil
+}
+
+// Copying functions
+
+// Copy returns a copy of the given RangeMap.
+func (m *RangeMap[K, V]) Copy() *RangeMap[K, V] {
+	return &RangeMap[K, V]{slices.Clone(m.entries), m.less}
+}
+
+// Iteration
+
+// Iterate returns an Iterator2 over the ranges in this RangeMap and their
+// values, in increasing order.
+func (m *RangeMap[K, V]) Iterate() Iterator2[Interval[K], V] {
+	return &entryIterator[Interval[K], V]{
+		entries: AsList(slices.Clone(m.entries)),
+	}
+}
+
+// Overlapping returns an Iterator2 over the ranges in this RangeMap which
+// overlap iv, and their values, in increasing order. The first and last
+// ranges returned may extend beyond iv.
+func (m *RangeMap[K, V]) Overlapping(iv Interval[K]) Iterator2[Interval[K], V] {
+	lo, hi := m.overlapping(iv)
+	return &entryIterator[Interval[K], V]{
+		entries: AsList(slices.Clone(m.entries[lo:hi])),
+	}
+}
+
+// Internal methods
+
+func (m *RangeMap[K, V]) entry(low, high K, v V) Entry[Interval[K], V] {
+	return Entry[Interval[K], V]{Interval[K]{low, high}, v}
+}
+
+// overlapping returns the range of indices [lo, hi) of entries overlapping
+// iv.
+func (m *RangeMap[K, V]) overlapping(iv Interval[K]) (lo, hi int) {
+	lo = sort.Search(len(m.entries), func(i int) bool {
+		return m.less(iv.Low, m.entries[i].Key.High)
+	})
+	hi = sort.Search(len(m.entries), func(i int) bool {
+		return !m.less(m.entries[i].Key.Low, iv.High)
+	})
+	return
+}
+
+// coalesce merges entries i and i+1, if they are adjacent and have the same
+// value.
+func (m *RangeMap[K, V]) coalesce(i int) {
+	if i < 0 || i+1 >= len(m.entries) {
+		return
+	}
+	a, b := m.entries[i], m.entries[i+1]
+	adjacent := !m.less(a.Key.High, b.Key.Low) && !m.less(b.Key.Low, a.Key.High)
+	if adjacent && a.Value == b.Value {
+		m.entries[i].Key.High = b.Key.High
+		m.entries = slices.Delete(m.entries, i+1, i+2)
+	}
+}
+
+// Errors
+
+func errKeyNotInRange(k any) error {
+	return fmt.Errorf("key not in any range in RangeMap: %v", k)
+}
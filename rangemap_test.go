@@ -0,0 +1,144 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+const rangeMapDomain = 60
+
+// rangeMapEntries returns the entries of m, in order.
+func rangeMapEntries(m *RangeMap[int, int]) []Entry[Interval[int], int] {
+	var entries []Entry[Interval[int], int]
+	for it := m.Iterate(); it.HasNext(); {
+		iv, v := it.Next()
+		entries = append(entries, Entry[Interval[int], int]{iv, v})
+	}
+	return entries
+}
+
+// coalescedEntries returns the coalesced ranges described by ref, which maps
+// each key in the domain to its value (or -1 if the key is not present).
+func coalescedEntries(ref []int) []Entry[Interval[int], int] {
+	var entries []Entry[Interval[int], int]
+	for k, v := range ref {
+		if v < 0 {
+			continue
+		}
+		if n := len(entries); n > 0 && entries[n-1].Key.High == k && entries[n-1].Value == v {
+			entries[n-1].Key.High++
+		} else {
+			entries = append(entries, Entry[Interval[int], int]{Interval[int]{k, k + 1}, v})
+		}
+	}
+	return entries
+}
+
+func TestRangeMapAgainstBruteForce(t *testing.T) {
+	r := rand.New(seeded(39))
+	m := NewRangeMap[int, int](intLess)
+	ref := make([]int, rangeMapDomain)
+	for i := range ref {
+		ref[i] = -1
+	}
+
+	for i := 0; i < 3000; i++ {
+		low := r.IntN(rangeMapDomain)
+		iv := Interval[int]{low, low + 1 + r.IntN(min(15, rangeMapDomain-low))}
+		if r.IntN(3) == 0 {
+			want := false
+			for k := iv.Low; k < iv.High; k++ {
+				want = want || ref[k] >= 0
+				ref[k] = -1
+			}
+			if got := m.Remove(iv); got != want {
+				t.Fatalf("Remove(%v) = %v, want %v", iv, got, want)
+			}
+		} else {
+			// few values, so that coalescing happens often
+			v := r.IntN(3)
+			if err := m.Set(iv, v); err != nil {
+				t.Fatal(err)
+			}
+			for k := iv.Low; k < iv.High; k++ {
+				ref[k] = v
+			}
+		}
+
+		want := coalescedEntries(ref)
+		if got := rangeMapEntries(m); !slices.Equal(got, want) {
+			t.Fatalf("after step %d: entries %v, want %v", i, got, want)
+		}
+		if m.Size() != len(want) {
+			t.Fatalf("Size() = %d, want %d", m.Size(), len(want))
+		}
+
+		k := r.IntN(rangeMapDomain)
+		v, err := m.Get(k)
+		if (err == nil) != (ref[k] >= 0) || (err == nil && v != ref[k]) {
+			t.Fatalf("Get(%d) = %d, %v; want %d", k, v, err, ref[k])
+		}
+		if m.Contains(k) != (ref[k] >= 0) {
+			t.Fatalf("Contains(%d) disagrees with Get", k)
+		}
+
+		var wantOverlap []Entry[Interval[int], int]
+		for _, e := range want {
+			if e.Key.Low < iv.High && iv.Low < e.Key.High {
+				wantOverlap = append(wantOverlap, e)
+			}
+		}
+		var gotOverlap []Entry[Interval[int], int]
+		for it := m.Overlapping(iv); it.HasNext(); {
+			iv, v := it.Next()
+			gotOverlap = append(gotOverlap, Entry[Interval[int], int]{iv, v})
+		}
+		if !slices.Equal(gotOverlap, wantOverlap) {
+			t.Fatalf("Overlapping(%v) = %v, want %v", iv, gotOverlap, wantOverlap)
+		}
+	}
+}
+
+func TestRangeMapCoalescing(t *testing.T) {
+	m := NewRangeMap[int, string](intLess)
+	m.Set(Interval[int]{0, 5}, "a")
+	m.Set(Interval[int]{10, 15}, "a")
+	m.Set(Interval[int]{5, 10}, "a")
+	if m.Size() != 1 {
+		t.Errorf("adjacent equal ranges were not coalesced: Size() = %d", m.Size())
+	}
+
+	// Overwriting the middle splits the range; restoring it coalesces again.
+	m.Set(Interval[int]{4, 6}, "b")
+	if m.Size() != 3 {
+		t.Errorf("Size() = %d after splitting, want 3", m.Size())
+	}
+	m.Set(Interval[int]{4, 6}, "a")
+	if m.Size() != 1 {
+		t.Errorf("Size() = %d after restoring, want 1", m.Size())
+	}
+
+	// Adjacent ranges with different values are kept apart.
+	m.Set(Interval[int]{15, 20}, "b")
+	if m.Size() != 2 {
+		t.Errorf("Size() = %d, want 2", m.Size())
+	}
+
+	if err := m.Set(Interval[int]{3, 3}, "c"); err == nil {
+		t.Errorf("expected error setting an empty interval")
+	}
+	if m.Remove(Interval[int]{30, 40}) || m.Remove(Interval[int]{5, 5}) {
+		t.Errorf("Remove returned true without removing any keys")
+	}
+
+	cp := m.Copy()
+	cp.Remove(Interval[int]{0, 20})
+	if !cp.IsEmpty() || m.Size() != 2 {
+		t.Errorf("modifying a copy changed the original")
+	}
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Errorf("RangeMap not empty after Clear")
+	}
+}
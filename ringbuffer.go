@@ -0,0 +1,194 @@
+package collections
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RingBuffer is a fixed-capacity circular buffer. When it is full, pushing a
+// new element overwrites the oldest one.
+type RingBuffer[T any] struct {
+	elems     []T
+	head      int // index of the oldest element
+	size      int
+	overflows int
+	// mu is nil if this RingBuffer is not safe for concurrent use.
+	mu *sync.Mutex
+}
+
+// Constructors
+
+// NewRingBuffer makes a new empty RingBuffer with the given capacity.
+// It panics if capacity is not positive.
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("non-positive RingBuffer capacity %d", capacity))
+	}
+	return &RingBuffer[T]{elems: make([]T, capacity)}
+}
+
+// NewConcurrentRingBuffer makes a new empty RingBuffer with the given
+// capacity, which is safe for concurrent use.
+// It panics if capacity is not positive.
+func NewConcurrentRingBuffer[T any](capacity int) *RingBuffer[T] {
+	r := NewRingBuffer[T](capacity)
+	r.mu = &sync.Mutex{}
+	return r
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of elements in this RingBuffer.
+func (r *RingBuffer[T]) Size() int {
+	r.lock()
+	defer r.unlock()
+	return r.size
+}
+
+// IsEmpty returns true if this RingBuffer is empty.
+func (r *RingBuffer[T]) IsEmpty() bool {
+	return r.Size() == 0
+}
+
+// IsFull returns true if this RingBuffer is full, i.e. the next Push will
+// overwrite the oldest element.
+func (r *RingBuffer[T]) IsFull() bool {
+	r.lock()
+	defer r.unlock()
+	return r.size == len(r.elems)
+}
+
+// Capacity returns the capacity of this RingBuffer.
+func (r *RingBuffer[T]) Capacity() int {
+	return len(r.elems)
+}
+
+// Overflows returns the number of elements which have been overwritten
+// because this RingBuffer was full.
+func (r *RingBuffer[T]) Overflows() int {
+	r.lock()
+	defer r.unlock()
+	return r.overflows
+}
+
+// Peek returns the oldest element of this RingBuffer, without removing it.
+// It returns an error if the RingBuffer is empty.
+func (r *RingBuffer[T]) Peek() (t T, err error) {
+	r.lock()
+	defer r.unlock()
+	if r.size == 0 {
+		err = errRingBufferEmpty
+		return
+	}
+	return r.elems[r.head], nil
+}
+
+// Get returns the element at index pos in this RingBuffer, where index 0 is
+// the oldest element. Negative indices count back from the newest element,
+// so index -1 is the newest element.
+// It returns an error if the given index is out of bounds.
+func (r *RingBuffer[T]) Get(pos int) (t T, err error) {
+	r.lock()
+	defer r.unlock()
+	i := pos
+	if i < 0 {
+		i += r.size
+	}
+	if i < 0 || i >= r.size {
+		err = r.errIndexOutOfBounds(pos)
+		return
+	}
+	return r.elems[r.physical(i)], nil
+}
+
+// Basic (mutating) functions
+
+// Push adds t as the newest element of this RingBuffer. If the RingBuffer
+// is full, the oldest element is overwritten.
+func (r *RingBuffer[T]) Push(t T) {
+	r.lock()
+	defer r.unlock()
+	if r.size == len(r.elems) {
+		r.elems[r.head] = t
+		r.head = r.physical(1)
+		r.overflows++
+		return
+	}
+	r.elems[r.physical(r.size)] = t
+	r.size++
+}
+
+// Pop removes the oldest element of this RingBuffer and returns it.
+// It returns an error if the RingBuffer is empty.
+func (r *RingBuffer[T]) Pop() (t T, err error) {
+	r.lock()
+	defer r.unlock()
+	if r.size == 0 {
+		err = errRingBufferEmpty
+		return
+	}
+	t = r.elems[r.head]
+	var zero T
+	r.elems[r.head] = zero
+	r.head = r.physical(1)
+	r.size--
+	return
+}
+
+// Clear removes all elements from this RingBuffer. The overflow count is
+// not reset.
+func (r *RingBuffer[T]) Clear() {
+	r.lock()
+	defer r.unlock()
+	clear(r.elems)
+	r.head = 0
+	r.size = 0
+}
+
+// Copying functions
+
+// Snapshot returns a List of the elements in this RingBuffer, from oldest to
+// newest.
+func (r *RingBuffer[T]) Snapshot() *List[T] {
+	r.lock()
+	defer r.unlock()
+	snapshot := NewList[T](r.size)
+	for i := 0; i < r.size; i++ {
+		snapshot.Append(r.elems[r.physical(i)])
+	}
+	return snapshot
+}
+
+// Iteration
+
+// Iterate returns an Iterator over a snapshot of the elements in this
+// RingBuffer, from oldest to newest.
+func (r *RingBuffer[T]) Iterate() Iterator[T] {
+	return r.Snapshot().Iterate()
+}
+
+// Internal methods
+
+// physical returns the index in r.elems of the element at logical index i.
+func (r *RingBuffer[T]) physical(i int) int {
+	return (r.head + i) % len(r.elems)
+}
+
+func (r *RingBuffer[T]) lock() {
+	if r.mu != nil {
+		r.mu.Lock()
+	}
+}
+
+func (r *RingBuffer[T]) unlock() {
+	if r.mu != nil {
+		r.mu.Unlock()
+	}
+}
+
+// Errors
+var errRingBufferEmpty = fmt.Errorf("ring buffer is empty")
+
+func (r *RingBuffer[T]) errIndexOutOfBounds(pos int) error {
+	return fmt.Errorf("index %d out of bounds in RingBuffer (size %d)", pos, r.size)
+}
@@ -0,0 +1,172 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRingBufferAgainstSlice(t *testing.T) {
+	r := rand.New(seeded(46))
+	for _, capacity := range []int{1, 2, 5, 16} {
+		b := NewRingBuffer[int](capacity)
+		var ref []int
+		overflows := 0
+		for i := 0; i < 2000; i++ {
+			switch r.IntN(5) {
+			case 0, 1, 2:
+				b.Push(i)
+				ref = append(ref, i)
+				if len(ref) > capacity {
+					ref = ref[1:]
+					overflows++
+				}
+			case 3:
+				got, err := b.Pop()
+				if len(ref) == 0 {
+					if err == nil {
+						t.Fatalf("Pop() on an empty buffer returned %d", got)
+					}
+					break
+				}
+				if err != nil || got != ref[0] {
+					t.Fatalf("Pop() = %d, %v; want %d", got, err, ref[0])
+				}
+				ref = ref[1:]
+			case 4:
+				if r.IntN(50) == 0 {
+					b.Clear()
+					ref = nil
+				}
+			}
+
+			if got := *b.Snapshot(); !slices.Equal(got, ref) {
+				t.Fatalf("Snapshot() = %v, want %v", got, ref)
+			}
+			if b.Size() != len(ref) || b.IsEmpty() != (len(ref) == 0) || b.IsFull() != (len(ref) == capacity) {
+				t.Fatalf("Size() = %d, IsFull() = %v; want %d", b.Size(), b.IsFull(), len(ref))
+			}
+			if b.Overflows() != overflows {
+				t.Fatalf("Overflows() = %d, want %d", b.Overflows(), overflows)
+			}
+			if v, err := b.Peek(); (err == nil) != (len(ref) > 0) || (err == nil && v != ref[0]) {
+				t.Fatalf("Peek() = %d, %v", v, err)
+			}
+			pos := r.IntN(2*capacity+2) - capacity - 1
+			i := pos
+			if i < 0 {
+				i += len(ref)
+			}
+			v, err := b.Get(pos)
+			if valid := i >= 0 && i < len(ref); (err == nil) != valid || (valid && v != ref[i]) {
+				t.Fatalf("Get(%d) = %d, %v with contents %v", pos, v, err, ref)
+			}
+		}
+		if b.Capacity() != capacity {
+			t.Errorf("Capacity() = %d, want %d", b.Capacity(), capacity)
+		}
+	}
+}
+
+func TestRingBufferReleasesElements(t *testing.T) {
+	b := NewRingBuffer[*int](3)
+	for i := 0; i < 3; i++ {
+		b.Push(new(int))
+	}
+	b.Pop()
+	b.Pop()
+	nils := 0
+	for _, p := range b.elems {
+		if p == nil {
+			nils++
+		}
+	}
+	if nils != 2 {
+		t.Errorf("%d slots cleared after two Pops, want 2", nils)
+	}
+
+	b.Clear()
+	if slices.ContainsFunc(b.elems, func(p *int) bool { return p != nil }) {
+		t.Errorf("Clear did not release the elements")
+	}
+}
+
+func TestRingBufferIterate(t *testing.T) {
+	b := NewRingBuffer[string](3)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		b.Push(s)
+	}
+	it := b.Iterate()
+	b.Push("e") // the iterator works on a snapshot
+	var got []string
+	for it.HasNext() {
+		got = append(got, it.Next())
+	}
+	if !slices.Equal(got, []string{"b", "c", "d"}) {
+		t.Errorf("Iterate() = %v", got)
+	}
+	b.Clear()
+	if b.Overflows() != 2 {
+		t.Errorf("Clear reset the overflow count to %d", b.Overflows())
+	}
+
+	if !panics(func() { NewRingBuffer[int](0) }) {
+		t.Errorf("expected panic for a non-positive capacity")
+	}
+}
+
+// TestConcurrentRingBuffer runs concurrent producers and consumers; run with
+// -race. Every pushed element is either popped, overwritten (counted by
+// Overflows), or left in the buffer.
+func TestConcurrentRingBuffer(t *testing.T) {
+	const producers, consumers, pushes = 4, 4, 2000
+	b := NewConcurrentRingBuffer[int](16)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < pushes; i++ {
+				b.Push(i)
+				b.Size()
+				b.Get(-1)
+			}
+		}()
+	}
+
+	popped := make([]int, consumers)
+	done := make(chan struct{})
+	var consumerWG sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		consumerWG.Add(1)
+		go func(c int) {
+			defer consumerWG.Done()
+			for {
+				if _, err := b.Pop(); err == nil {
+					popped[c]++
+					continue
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+				b.Snapshot()
+			}
+		}(c)
+	}
+
+	wg.Wait()
+	close(done)
+	consumerWG.Wait()
+
+	total := b.Size() + b.Overflows()
+	for _, n := range popped {
+		total += n
+	}
+	if total != producers*pushes {
+		t.Errorf("%d elements accounted for, want %d", total, producers*pushes)
+	}
+}
@@ -0,0 +1,944 @@
+package collections
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// RoaringBitmap is an implementation of a set of uint32 values using a
+// compressed ("roaring") bitmap. It is much more compact than a Set[uint32]
+// or a BitSet for large sets of values which are spread over a wide range.
+//
+// Values are partitioned by their high 16 bits into chunks, and the low 16
+// bits of the values in each chunk are stored in a container. A container is
+// either a sorted array (for sparse chunks), a bitmap (for dense chunks), or
+// a list of runs of consecutive values (after calling RunOptimize).
+type RoaringBitmap struct {
+	// keys holds the high 16 bits of each chunk, in increasing order.
+	keys []uint16
+	// containers[i] holds the low 16 bits of the values in chunk keys[i].
+	containers []container
+}
+
+// maxArraySize is the maximum number of elements in an array container.
+// Above this, a bitmap container (of 8KiB) is smaller.
+const maxArraySize = 4096
+
+// bitmapWords is the number of words in a bitmap container.
+const bitmapWords = 1 << 16 / wordSize
+
+// String returns a string representation of this RoaringBitmap.
+func (b *RoaringBitmap) String() string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	it := b.Iterate()
+	for it.HasNext() {
+		if sb.Len() > 1 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%d", it.Next())
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// Constructors
+
+// NewRoaringBitmap makes a new empty RoaringBitmap.
+func NewRoaringBitmap() *RoaringBitmap {
+	return &RoaringBitmap{}
+}
+
+// AsRoaringBitmap returns a RoaringBitmap containing the elements of the
+// given slice.
+func AsRoaringBitmap(elems []uint32) *RoaringBitmap {
+	b := NewRoaringBitmap()
+	for _, x := range elems {
+		b.Add(x)
+	}
+	return b
+}
+
+// RoaringBitmapFromSet returns a RoaringBitmap containing the elements of the
+// given Set.
+func RoaringBitmapFromSet(s *Set[uint32]) *RoaringBitmap {
+	return AsRoaringBitmap(s.Slice())
+}
+
+// ToSet returns a Set containing the elements of this RoaringBitmap.
+func (b *RoaringBitmap) ToSet() *Set[uint32] {
+	s := NewSet[uint32](b.Cardinality())
+	it := b.Iterate()
+	for it.HasNext() {
+		s.Add(it.Next())
+	}
+	return s
+}
+
+// Slice returns a slice containing the elements of this RoaringBitmap, in
+// increasing order.
+func (b *RoaringBitmap) Slice() []uint32 {
+	slice := make([]uint32, 0, b.Cardinality())
+	it := b.Iterate()
+	for it.HasNext() {
+		slice = append(slice, it.Next())
+	}
+	return slice
+}
+
+// Basic (non-mutating) functions
+
+// Cardinality returns the number of elements in this RoaringBitmap.
+func (b *RoaringBitmap) Cardinality() int {
+	card := 0
+	for _, c := range b.containers {
+		card += c.cardinality()
+	}
+	return card
+}
+
+// Size returns the number of elements in this RoaringBitmap. It is
+// equivalent to Cardinality.
+func (b *RoaringBitmap) Size() int {
+	return b.Cardinality()
+}
+
+// IsEmpty returns true if this RoaringBitmap is empty.
+func (b *RoaringBitmap) IsEmpty() bool {
+	return len(b.containers) == 0
+}
+
+// Contains returns true if the given value is in the RoaringBitmap.
+func (b *RoaringBitmap) Contains(x uint32) bool {
+	i, ok := b.find(high(x))
+	return ok && b.containers[i].contains(low(x))
+}
+
+// Rank returns the number of elements of this RoaringBitmap which are less
+// than or equal to x.
+func (b *RoaringBitmap) Rank(x uint32) int {
+	rank := 0
+	for i, key := range b.keys {
+		if key > high(x) {
+			break
+		}
+		if key < high(x) {
+			rank += b.containers[i].cardinality()
+		} else {
+			rank += b.containers[i].rank(low(x))
+		}
+	}
+	return rank
+}
+
+// Select returns the i-th smallest element of this RoaringBitmap, counting
+// from 0, so that b.Rank(b.Select(i)) == i+1.
+// It returns an error if i is out of bounds.
+func (b *RoaringBitmap) Select(i int) (uint32, error) {
+	if i < 0 {
+		return 0, errRankOutOfBounds(i, b.Cardinality())
+	}
+	n := i
+	for j, c := range b.containers {
+		card := c.cardinality()
+		if n < card {
+			return combine(b.keys[j], c.selectAt(n)), nil
+		}
+		n -= card
+	}
+	return 0, errRankOutOfBounds(i, b.Cardinality())
+}
+
+// Basic (mutating) functions
+
+// Add adds x to this RoaringBitmap, if it is not already present.
+func (b *RoaringBitmap) Add(x uint32) {
+	i, ok := b.find(high(x))
+	if !ok {
+		b.insertContainer(i, high(x), arrayContainer{low(x)})
+		return
+	}
+	b.containers[i] = b.containers[i].add(low(x))
+}
+
+// Remove removes x from this RoaringBitmap. It returns false if x was not in
+// the RoaringBitmap to begin with, and returns true if x was removed.
+func (b *RoaringBitmap) Remove(x uint32) bool {
+	i, ok := b.find(high(x))
+	if !ok || !b.containers[i].contains(low(x)) {
+		return false
+	}
+	b.setContainer(i, b.containers[i].remove(low(x)))
+	return true
+}
+
+// AddRange adds all values in the range [low, high) to this RoaringBitmap.
+func (b *RoaringBitmap) AddRange(low, high uint64) {
+	if high > 1<<32 {
+		high = 1 << 32
+	}
+	for low < high {
+		key := uint16(low >> 16)
+		// end of this chunk, or high if sooner
+		end := min(uint64(key)<<16+1<<16, high)
+
+		var words [bitmapWords]uint64
+		i, ok := b.find(key)
+		if ok {
+			words = b.containers[i].words()
+		}
+		setRange(&words, int(low&0xFFFF), int(end-uint64(key)<<16))
+		if ok {
+			b.containers[i] = fromWords(&words)
+		} else {
+			b.insertContainer(i, key, fromWords(&words))
+		}
+		low = end
+	}
+}
+
+// Clear removes all elements from this RoaringBitmap.
+func (b *RoaringBitmap) Clear() {
+	b.keys = nil
+	b.containers = nil
+}
+
+// RunOptimize converts each container to a run container if that would use
+// less memory, or converts run containers back if not. This is worthwhile
+// when the RoaringBitmap contains long runs of consecutive values.
+func (b *RoaringBitmap) RunOptimize() {
+	for i, c := range b.containers {
+		values := c.values()
+		runs := toRuns(values)
+		if runContainerSize(len(runs)) < sizeOf(fromValues(values)) {
+			b.containers[i] = runContainer(runs)
+		} else {
+			b.containers[i] = fromValues(values)
+		}
+	}
+}
+
+// Copying functions
+
+// Copy returns a copy of the given RoaringBitmap.
+func (b *RoaringBitmap) Copy() *RoaringBitmap {
+	cp := &RoaringBitmap{
+		keys:       make([]uint16, len(b.keys)),
+		containers: make([]container, len(b.containers)),
+	}
+	copy(cp.keys, b.keys)
+	for i, c := range b.containers {
+		cp.containers[i] = c.clone()
+	}
+	return cp
+}
+
+// Iteration
+
+type roaringIterator struct {
+	b *RoaringBitmap
+	// index of the current container
+	index int
+	// values in the current container, and position in values
+	values []uint16
+	pos    int
+}
+
+func (i *roaringIterator) HasNext() bool {
+	for i.pos >= len(i.values) {
+		if i.index >= len(i.b.containers) {
+			return false
+		}
+		i.values = i.b.containers[i.index].values()
+		i.pos = 0
+		i.index++
+	}
+	return true
+}
+
+func (i *roaringIterator) Next() uint32 {
+	i.HasNext()
+	x := combine(i.b.keys[i.index-1], i.values[i.pos])
+	i.pos++
+	return x
+}
+
+// Iterate returns an Iterator iterating over the elements of this
+// RoaringBitmap in increasing order. The RoaringBitmap must not be modified
+// during iteration.
+func (b *RoaringBitmap) Iterate() Iterator[uint32] {
+	return &roaringIterator{b: b}
+}
+
+// Set operations
+
+// Or returns the RoaringBitmap of all elements which are in this
+// RoaringBitmap or any of the given RoaringBitmaps. It is the RoaringBitmap
+// equivalent of Union.
+func (b *RoaringBitmap) Or(others ...*RoaringBitmap) *RoaringBitmap {
+	or := b
+	for _, other := range others {
+		or = roaringOp(or, other, true, true, func(x, y uint64) uint64 { return x | y })
+	}
+	if or == b {
+		or = b.Copy()
+	}
+	return or
+}
+
+// And returns the RoaringBitmap of elements which are in this RoaringBitmap
+// and all of the given RoaringBitmaps. It is the RoaringBitmap equivalent of
+// Intersection.
+func (b *RoaringBitmap) And(others ...*RoaringBitmap) *RoaringBitmap {
+	and := b
+	for _, other := range others {
+		and = roaringOp(and, other, false, false, func(x, y uint64) uint64 { return x & y })
+	}
+	if and == b {
+		and = b.Copy()
+	}
+	return and
+}
+
+// AndNot returns the RoaringBitmap of all elements in this RoaringBitmap
+// which are not in other. It is the RoaringBitmap equivalent of Difference.
+func (b *RoaringBitmap) AndNot(other *RoaringBitmap) *RoaringBitmap {
+	return roaringOp(b, other, true, false, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// Xor returns the RoaringBitmap of all elements which are in exactly one of
+// this RoaringBitmap and other. It is the RoaringBitmap equivalent of
+// SymmetricDifference.
+func (b *RoaringBitmap) Xor(other *RoaringBitmap) *RoaringBitmap {
+	return roaringOp(b, other, true, true, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// roaringOp returns a new RoaringBitmap computed from b1 and b2 chunk by
+// chunk. Chunks present in both are combined by applying op to each pair of
+// words. Chunks present in only b1 (resp. b2) are copied if keep1 (resp.
+// keep2) is true, and dropped otherwise.
+func roaringOp(b1, b2 *RoaringBitmap, keep1, keep2 bool, op func(x, y uint64) uint64) *RoaringBitmap {
+	res := NewRoaringBitmap()
+	i, j := 0, 0
+	for i < len(b1.keys) || j < len(b2.keys) {
+		switch {
+		case j >= len(b2.keys) || (i < len(b1.keys) && b1.keys[i] < b2.keys[j]):
+			if keep1 {
+				res.appendContainer(b1.keys[i], b1.containers[i].clone())
+			}
+			i++
+		case i >= len(b1.keys) || b2.keys[j] < b1.keys[i]:
+			if keep2 {
+				res.appendContainer(b2.keys[j], b2.containers[j].clone())
+			}
+			j++
+		default:
+			w1, w2 := b1.containers[i].words(), b2.containers[j].words()
+			for k := range w1 {
+				w1[k] = op(w1[k], w2[k])
+			}
+			res.appendContainer(b1.keys[i], fromWords(&w1))
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// Serialization
+
+// roaringMagic identifies the RoaringBitmap binary format.
+var roaringMagic = [4]byte{'R', 'B', 'M', 1}
+
+const (
+	arrayContainerType byte = iota
+	bitmapContainerType
+	runContainerType
+)
+
+// MarshalBinary encodes this RoaringBitmap in a portable binary format,
+// implementing encoding.BinaryMarshaler.
+//
+// The format is: the 4-byte header "RBM\x01", the number of containers as a
+// uint32, then for each container its key (uint16), type (byte: 0 = array,
+// 1 = bitmap, 2 = run) and contents. Array contents are a uint32 count
+// followed by that many uint16 values; bitmap contents are 1024 uint64
+// words; run contents are a uint32 count followed by that many
+// (start, length-1) uint16 pairs. All integers are little-endian.
+func (b *RoaringBitmap) MarshalBinary() ([]byte, error) {
+	data := append([]byte{}, roaringMagic[:]...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(b.keys)))
+	for i, key := range b.keys {
+		data = binary.LittleEndian.AppendUint16(data, key)
+		switch c := b.containers[i].(type) {
+		case arrayContainer:
+			data = append(data, arrayContainerType)
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c)))
+			for _, x := range c {
+				data = binary.LittleEndian.AppendUint16(data, x)
+			}
+		case *bitmapContainer:
+			data = append(data, bitmapContainerType)
+			for _, w := range c.w {
+				data = binary.LittleEndian.AppendUint64(data, w)
+			}
+		case runContainer:
+			data = append(data, runContainerType)
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c)))
+			for _, r := range c {
+				data = binary.LittleEndian.AppendUint16(data, r.start)
+				data = binary.LittleEndian.AppendUint16(data, r.length)
+			}
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes data produced by MarshalBinary into this
+// RoaringBitmap, replacing its contents. It implements
+// encoding.BinaryUnmarshaler.
+func (b *RoaringBitmap) UnmarshalBinary(data []byte) error {
+	r := binaryReader{data: data}
+	if magic := r.next(4); magic == nil || [4]byte(magic) != roaringMagic {
+		return errInvalidEncoding("RoaringBitmap", "bad header")
+	}
+	n := r.uint32()
+
+	res := NewRoaringBitmap()
+	for k := uint32(0); k < n && r.err == nil; k++ {
+		key := r.uint16()
+		if len(res.keys) > 0 && key <= res.keys[len(res.keys)-1] {
+			return errInvalidEncoding("RoaringBitmap", "keys not increasing")
+		}
+
+		var c container
+		switch typ := r.byte(); typ {
+		case arrayContainerType:
+			count := r.uint32()
+			if count == 0 || count > maxArraySize {
+				return errInvalidEncoding("RoaringBitmap", "bad array size")
+			}
+			a := make(arrayContainer, 0, count)
+			for i := uint32(0); i < count && r.err == nil; i++ {
+				a = append(a, r.uint16())
+			}
+			c = a
+		case bitmapContainerType:
+			bc := &bitmapContainer{}
+			for i := range bc.w {
+				bc.w[i] = r.uint64()
+			}
+			c = bc.recount()
+		case runContainerType:
+			count := r.uint32()
+			if count == 0 || count > 1<<15 {
+				return errInvalidEncoding("RoaringBitmap", "bad run count")
+			}
+			rc := make(runContainer, 0, count)
+			for i := uint32(0); i < count && r.err == nil; i++ {
+				rc = append(rc, run{r.uint16(), r.uint16()})
+			}
+			c = rc
+		default:
+			return errInvalidEncoding("RoaringBitmap", fmt.Sprintf("unknown container type %d", typ))
+		}
+		if r.err != nil {
+			break
+		}
+		if c == nil {
+			// a bitmap container with no bits set
+			return errInvalidEncoding("RoaringBitmap", "empty container")
+		}
+		if !c.valid() {
+			return errInvalidEncoding("RoaringBitmap", "malformed container")
+		}
+		res.appendContainer(key, c)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) != 0 {
+		return errInvalidEncoding("RoaringBitmap", "trailing data")
+	}
+
+	*b = *res
+	return nil
+}
+
+// Internal methods
+
+// find returns the index of the container with the given key, and whether it
+// exists. If not, the index is where it would be inserted.
+func (b *RoaringBitmap) find(key uint16) (int, bool) {
+	i := sort.Search(len(b.keys), func(i int) bool { return b.keys[i] >= key })
+	return i, i < len(b.keys) && b.keys[i] == key
+}
+
+func (b *RoaringBitmap) insertContainer(i int, key uint16, c container) {
+	if c == nil {
+		return
+	}
+	b.keys = append(b.keys, 0)
+	copy(b.keys[i+1:], b.keys[i:])
+	b.keys[i] = key
+	b.containers = append(b.containers, nil)
+	copy(b.containers[i+1:], b.containers[i:])
+	b.containers[i] = c
+}
+
+// appendContainer adds a container with a key larger than all existing keys.
+// It does nothing if c is nil (i.e. empty).
+func (b *RoaringBitmap) appendContainer(key uint16, c container) {
+	if c != nil {
+		b.keys = append(b.keys, key)
+		b.containers = append(b.containers, c)
+	}
+}
+
+// setContainer replaces the container at index i, removing it if c is nil
+// (i.e. empty).
+func (b *RoaringBitmap) setContainer(i int, c container) {
+	if c != nil {
+		b.containers[i] = c
+		return
+	}
+	b.keys = append(b.keys[:i], b.keys[i+1:]...)
+	b.containers = append(b.containers[:i], b.containers[i+1:]...)
+}
+
+func high(x uint32) uint16 {
+	return uint16(x >> 16)
+}
+
+func low(x uint32) uint16 {
+	return uint16(x)
+}
+
+func combine(high, low uint16) uint32 {
+	return uint32(high)<<16 | uint32(low)
+}
+
+// Containers
+
+// container stores a non-empty set of uint16 values. Mutating methods return
+// the resulting container, which may have a different representation, or be
+// nil if it is empty.
+type container interface {
+	contains(x uint16) bool
+	add(x uint16) container
+	remove(x uint16) container
+	cardinality() int
+	// rank returns the number of elements less than or equal to x.
+	rank(x uint16) int
+	// selectAt returns the i-th smallest element.
+	selectAt(i int) uint16
+	// values returns the elements in increasing order.
+	values() []uint16
+	// words returns the elements as a bitmap.
+	words() [bitmapWords]uint64
+	clone() container
+	// valid checks the container invariants, for decoding.
+	valid() bool
+}
+
+// fromValues returns the best non-run container for the given sorted values.
+func fromValues(values []uint16) container {
+	if len(values) == 0 {
+		return nil
+	}
+	if len(values) <= maxArraySize {
+		return arrayContainer(values)
+	}
+	bc := &bitmapContainer{card: len(values)}
+	for _, x := range values {
+		bc.w[x/wordSize] |= 1 << (x % wordSize)
+	}
+	return bc
+}
+
+// fromWords returns the best non-run container for the given bitmap.
+func fromWords(words *[bitmapWords]uint64) container {
+	bc := &bitmapContainer{w: *words}
+	return bc.recount()
+}
+
+// sizeOf returns the approximate size of a container in bytes.
+func sizeOf(c container) int {
+	switch c := c.(type) {
+	case arrayContainer:
+		return 2 * len(c)
+	case runContainer:
+		return runContainerSize(len(c))
+	default:
+		return 8 * bitmapWords
+	}
+}
+
+func runContainerSize(runs int) int {
+	return 4 * runs
+}
+
+// setRange sets the bits [low, high) in words.
+func setRange(words *[bitmapWords]uint64, low, high int) {
+	for i := low; i < high; {
+		if i%wordSize == 0 && i+wordSize <= high {
+			words[i/wordSize] = ^uint64(0)
+			i += wordSize
+			continue
+		}
+		words[i/wordSize] |= 1 << (i % wordSize)
+		i++
+	}
+}
+
+// arrayContainer is a sorted slice of at most maxArraySize values.
+type arrayContainer []uint16
+
+func (a arrayContainer) search(x uint16) int {
+	return sort.Search(len(a), func(i int) bool { return a[i] >= x })
+}
+
+func (a arrayContainer) contains(x uint16) bool {
+	i := a.search(x)
+	return i < len(a) && a[i] == x
+}
+
+func (a arrayContainer) add(x uint16) container {
+	i := a.search(x)
+	if i < len(a) && a[i] == x {
+		return a
+	}
+	if len(a) == maxArraySize {
+		values := make([]uint16, 0, len(a)+1)
+		values = append(append(append(values, a[:i]...), x), a[i:]...)
+		return fromValues(values)
+	}
+	a = append(a, 0)
+	copy(a[i+1:], a[i:])
+	a[i] = x
+	return a
+}
+
+func (a arrayContainer) remove(x uint16) container {
+	i := a.search(x)
+	if i < len(a) && a[i] == x {
+		a = append(a[:i], a[i+1:]...)
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	return a
+}
+
+func (a arrayContainer) cardinality() int {
+	return len(a)
+}
+
+func (a arrayContainer) rank(x uint16) int {
+	return sort.Search(len(a), func(i int) bool { return a[i] > x })
+}
+
+func (a arrayContainer) selectAt(i int) uint16 {
+	return a[i]
+}
+
+func (a arrayContainer) values() []uint16 {
+	return a
+}
+
+func (a arrayContainer) words() (w [bitmapWords]uint64) {
+	for _, x := range a {
+		w[x/wordSize] |= 1 << (x % wordSize)
+	}
+	return
+}
+
+func (a arrayContainer) clone() container {
+	cp := make(arrayContainer, len(a))
+	copy(cp, a)
+	return cp
+}
+
+func (a arrayContainer) valid() bool {
+	for i := 1; i < len(a); i++ {
+		if a[i-1] >= a[i] {
+			return false
+		}
+	}
+	return len(a) > 0 && len(a) <= maxArraySize
+}
+
+// bitmapContainer is a bitmap of more than maxArraySize values.
+type bitmapContainer struct {
+	w    [bitmapWords]uint64
+	card int
+}
+
+// recount recomputes the cardinality, and converts to an array container if
+// the cardinality is small enough.
+func (bc *bitmapContainer) recount() container {
+	bc.card = 0
+	for _, w := range bc.w {
+		bc.card += bits.OnesCount64(w)
+	}
+	if bc.card <= maxArraySize {
+		return fromValues(bc.values())
+	}
+	return bc
+}
+
+func (bc *bitmapContainer) contains(x uint16) bool {
+	return bc.w[x/wordSize]&(1<<(x%wordSize)) != 0
+}
+
+func (bc *bitmapContainer) add(x uint16) container {
+	if !bc.contains(x) {
+		bc.w[x/wordSize] |= 1 << (x % wordSize)
+		bc.card++
+	}
+	return bc
+}
+
+func (bc *bitmapContainer) remove(x uint16) container {
+	if bc.contains(x) {
+		bc.w[x/wordSize] &^= 1 << (x % wordSize)
+		bc.card--
+	}
+	if bc.card <= maxArraySize {
+		return fromValues(bc.values())
+	}
+	return bc
+}
+
+func (bc *bitmapContainer) cardinality() int {
+	return bc.card
+}
+
+func (bc *bitmapContainer) rank(x uint16) int {
+	rank := 0
+	for _, w := range bc.w[:x/wordSize] {
+		rank += bits.OnesCount64(w)
+	}
+	// count bits up to and including x in its word
+	mask := ^uint64(0) >> (wordSize - 1 - x%wordSize)
+	return rank + bits.OnesCount64(bc.w[x/wordSize]&mask)
+}
+
+func (bc *bitmapContainer) selectAt(i int) uint16 {
+	for k, w := range bc.w {
+		n := bits.OnesCount64(w)
+		if i >= n {
+			i -= n
+			continue
+		}
+		for ; i > 0; i-- {
+			w &= w - 1 // clear lowest set bit
+		}
+		return uint16(k*wordSize + bits.TrailingZeros64(w))
+	}
+	panic("selectAt out of range")
+}
+
+func (bc *bitmapContainer) values() []uint16 {
+	values := make([]uint16, 0, bc.card)
+	for k, w := range bc.w {
+		for w != 0 {
+			values = append(values, uint16(k*wordSize+bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+	return values
+}
+
+func (bc *bitmapContainer) words() [bitmapWords]uint64 {
+	return bc.w
+}
+
+func (bc *bitmapContainer) clone() container {
+	cp := *bc
+	return &cp
+}
+
+func (bc *bitmapContainer) valid() bool {
+	return bc.card > maxArraySize
+}
+
+// run is a sequence of consecutive values start, ..., start+length.
+// length is one less than the number of values, so that a run can cover the
+// whole range of uint16.
+type run struct {
+	start, length uint16
+}
+
+func (r run) last() uint16 {
+	return r.start + r.length
+}
+
+// runContainer is a sorted slice of non-overlapping, non-adjacent runs.
+type runContainer []run
+
+// toRuns converts sorted values to runs.
+func toRuns(values []uint16) []run {
+	var runs []run
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		runs = append(runs, run{values[i], uint16(j - i)})
+		i = j + 1
+	}
+	return runs
+}
+
+func (rc runContainer) contains(x uint16) bool {
+	i := sort.Search(len(rc), func(i int) bool { return rc[i].last() >= x })
+	return i < len(rc) && rc[i].start <= x
+}
+
+// Run containers are optimised for reading, so they are converted back to
+// array or bitmap containers when modified.
+
+func (rc runContainer) add(x uint16) container {
+	if rc.contains(x) {
+		return rc
+	}
+	return fromValues(rc.values()).add(x)
+}
+
+func (rc runContainer) remove(x uint16) container {
+	if !rc.contains(x) {
+		return rc
+	}
+	return fromValues(rc.values()).remove(x)
+}
+
+func (rc runContainer) cardinality() int {
+	card := 0
+	for _, r := range rc {
+		card += int(r.length) + 1
+	}
+	return card
+}
+
+func (rc runContainer) rank(x uint16) int {
+	rank := 0
+	for _, r := range rc {
+		if r.start > x {
+			break
+		}
+		if r.last() <= x {
+			rank += int(r.length) + 1
+		} else {
+			rank += int(x-r.start) + 1
+		}
+	}
+	return rank
+}
+
+func (rc runContainer) selectAt(i int) uint16 {
+	for _, r := range rc {
+		if i <= int(r.length) {
+			return r.start + uint16(i)
+		}
+		i -= int(r.length) + 1
+	}
+	panic("selectAt out of range")
+}
+
+func (rc runContainer) values() []uint16 {
+	values := make([]uint16, 0, rc.cardinality())
+	for _, r := range rc {
+		for x := int(r.start); x <= int(r.last()); x++ {
+			values = append(values, uint16(x))
+		}
+	}
+	return values
+}
+
+func (rc runContainer) words() (w [bitmapWords]uint64) {
+	for _, r := range rc {
+		setRange(&w, int(r.start), int(r.last())+1)
+	}
+	return
+}
+
+func (rc runContainer) clone() container {
+	cp := make(runContainer, len(rc))
+	copy(cp, rc)
+	return cp
+}
+
+func (rc runContainer) valid() bool {
+	for i, r := range rc {
+		if int(r.start)+int(r.length) > 0xFFFF {
+			return false
+		}
+		// runs must be increasing and separated by at least one value
+		if i > 0 && int(rc[i-1].last())+1 >= int(r.start) {
+			return false
+		}
+	}
+	return len(rc) > 0
+}
+
+// binaryReader reads little-endian integers from a byte slice, recording an
+// error if the data runs out.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errInvalidEncoding("data", "unexpected end of data")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *binaryReader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *binaryReader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *binaryReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *binaryReader) uint64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// Errors
+
+func errRankOutOfBounds(i, card int) error {
+	return fmt.Errorf("rank %d out of bounds in RoaringBitmap (cardinality %d)", i, card)
+}
+
+func errInvalidEncoding(typ, reason string) error {
+	return fmt.Errorf("invalid %s encoding: %s", typ, reason)
+}
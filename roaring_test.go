@@ -0,0 +1,326 @@
+package collections
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// randomRoaring returns a RoaringBitmap and an equal Set, mixing sparse
+// values (array containers), dense chunks (bitmap containers) and long runs
+// (run containers, after RunOptimize).
+func randomRoaring(r *rand.Rand) (*RoaringBitmap, *Set[uint32]) {
+	b, s := NewRoaringBitmap(), NewSet[uint32](0)
+	add := func(x uint32) {
+		b.Add(x)
+		s.Add(x)
+	}
+
+	for i := r.IntN(200); i > 0; i-- {
+		add(r.Uint32())
+	}
+	for i := r.IntN(3); i > 0; i-- {
+		base := uint32(r.IntN(8)) << 16
+		for j := 0; j < 5000; j++ {
+			add(base | uint32(r.IntN(1<<16)))
+		}
+	}
+	for i := r.IntN(3); i > 0; i-- {
+		lo := uint64(r.IntN(1 << 20))
+		hi := lo + uint64(r.IntN(100000))
+		b.AddRange(lo, hi)
+		for x := lo; x < hi; x++ {
+			s.Add(uint32(x))
+		}
+	}
+	if r.IntN(2) == 0 {
+		b.RunOptimize()
+	}
+	return b, s
+}
+
+func sortedSlice(s *Set[uint32]) []uint32 {
+	elems := s.Slice()
+	slices.Sort(elems)
+	return elems
+}
+
+func assertRoaringMatches(t *testing.T, b *RoaringBitmap, s *Set[uint32]) {
+	t.Helper()
+	if got, want := b.Slice(), sortedSlice(s); !slices.Equal(got, want) {
+		t.Fatalf("RoaringBitmap has %d elements, want %d (or elements differ)", len(got), len(want))
+	}
+	if b.Cardinality() != s.Size() {
+		t.Fatalf("Cardinality() = %d, want %d", b.Cardinality(), s.Size())
+	}
+}
+
+func TestRoaringAgainstSet(t *testing.T) {
+	r := rand.New(seeded(34))
+	b, s := NewRoaringBitmap(), NewSet[uint32](0)
+	for i := 0; i < 20000; i++ {
+		// Concentrate values in a few chunks so containers change type.
+		x := uint32(r.IntN(3))<<16 | uint32(r.IntN(6000))
+		if r.IntN(3) == 0 {
+			if b.Remove(x) != s.Remove(x) {
+				t.Fatalf("Remove(%d) disagrees with Set", x)
+			}
+		} else {
+			b.Add(x)
+			s.Add(x)
+		}
+		if b.Contains(x) != s.Contains(x) {
+			t.Fatalf("Contains(%d) disagrees with Set", x)
+		}
+		if i%1000 == 0 {
+			b.RunOptimize()
+			assertRoaringMatches(t, b, s)
+		}
+	}
+	assertRoaringMatches(t, b, s)
+	if !b.ToSet().Equal(s) || !RoaringBitmapFromSet(s).ToSet().Equal(s) {
+		t.Errorf("Set conversion does not round-trip")
+	}
+}
+
+func TestRoaringRankSelect(t *testing.T) {
+	r := rand.New(seeded(35))
+	for trial := 0; trial < 10; trial++ {
+		b, s := randomRoaring(r)
+		elems := sortedSlice(s)
+
+		for i := 0; i < 500 && len(elems) > 0; i++ {
+			j := r.IntN(len(elems))
+			x, err := b.Select(j)
+			if err != nil || x != elems[j] {
+				t.Fatalf("Select(%d) = %d, %v; want %d", j, x, err, elems[j])
+			}
+			if rank := b.Rank(x); rank != j+1 {
+				t.Fatalf("Rank(Select(%d)) = %d, want %d", j, rank, j+1)
+			}
+
+			y := r.Uint32()
+			want, _ := slices.BinarySearch(elems, y)
+			if s.Contains(y) {
+				want++
+			}
+			if rank := b.Rank(y); rank != want {
+				t.Fatalf("Rank(%d) = %d, want %d", y, rank, want)
+			}
+		}
+		if _, err := b.Select(len(elems)); err == nil {
+			t.Errorf("expected error from Select(Cardinality())")
+		}
+		if _, err := b.Select(-1); err == nil {
+			t.Errorf("expected error from Select(-1)")
+		}
+	}
+}
+
+func TestRoaringSetAlgebra(t *testing.T) {
+	r := rand.New(seeded(36))
+	for trial := 0; trial < 20; trial++ {
+		b1, s1 := randomRoaring(r)
+		b2, s2 := randomRoaring(r)
+		b3, s3 := randomRoaring(r)
+
+		assertRoaringMatches(t, b1.Or(b2, b3), Union(s1, s2, s3))
+		assertRoaringMatches(t, b1.And(b2, b3), Intersection(s1, s2, s3))
+		assertRoaringMatches(t, b1.And(b1.Or(b2)), s1)
+		assertRoaringMatches(t, b1.AndNot(b2), Difference(s1, s2))
+		assertRoaringMatches(t, b1.Xor(b2), SymmetricDifference(s1, s2))
+		assertRoaringMatches(t, b1, s1)
+	}
+}
+
+func TestRoaringMarshalRoundTrip(t *testing.T) {
+	r := rand.New(seeded(37))
+	for trial := 0; trial < 20; trial++ {
+		b, s := randomRoaring(r)
+		data, err := b.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := AsRoaringBitmap([]uint32{1, 2, 3})
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		assertRoaringMatches(t, got, s)
+	}
+
+	empty, _ := NewRoaringBitmap().MarshalBinary()
+	got := AsRoaringBitmap([]uint32{1})
+	if err := got.UnmarshalBinary(empty); err != nil || !got.IsEmpty() {
+		t.Errorf("empty round trip gave %v, %v", got.Slice(), err)
+	}
+}
+
+func roaringHeader(containers uint32) []byte {
+	return binary.LittleEndian.AppendUint32(append([]byte{}, roaringMagic[:]...), containers)
+}
+
+func TestRoaringUnmarshalMalformed(t *testing.T) {
+	valid, _ := AsRoaringBitmap([]uint32{1, 2, 70000}).MarshalBinary()
+
+	zeroBitmap := append(roaringHeader(1), 0, 0, bitmapContainerType)
+	zeroBitmap = append(zeroBitmap, make([]byte, bitmapWords*8)...)
+
+	emptyArray := append(roaringHeader(1), 0, 0, arrayContainerType, 0, 0, 0, 0)
+
+	unsorted := append(roaringHeader(1), 0, 0, arrayContainerType, 2, 0, 0, 0)
+	unsorted = append(unsorted, 5, 0, 3, 0)
+
+	repeatedKey := append(roaringHeader(2), 0, 0, arrayContainerType, 1, 0, 0, 0, 1, 0)
+	repeatedKey = append(repeatedKey, 0, 0, arrayContainerType, 1, 0, 0, 0, 2, 0)
+
+	tests := map[string][]byte{
+		"nil":                  nil,
+		"bad header":           []byte("RBM\x02\x00\x00\x00\x00"),
+		"truncated count":      valid[:6],
+		"truncated container":  valid[:len(valid)-1],
+		"trailing data":        append(slices.Clone(valid), 0),
+		"zero bitmap":          zeroBitmap,
+		"truncated bitmap":     zeroBitmap[:100],
+		"empty array":          emptyArray,
+		"unsorted array":       unsorted,
+		"repeated key":         repeatedKey,
+		"unknown type":         append(roaringHeader(1), 0, 0, 7),
+		"missing containers":   roaringHeader(3),
+		"empty run container":  append(roaringHeader(1), 0, 0, runContainerType, 0, 0, 0, 0),
+		"run past end of key":  append(roaringHeader(1), 0, 0, runContainerType, 1, 0, 0, 0, 0xF0, 0xFF, 0xFF, 0),
+		"overlapping runs":     append(roaringHeader(1), 0, 0, runContainerType, 2, 0, 0, 0, 0, 0, 5, 0, 3, 0, 1, 0),
+		"oversized array size": append(roaringHeader(1), 0, 0, arrayContainerType, 0xFF, 0xFF, 0, 0),
+	}
+	for name, data := range tests {
+		b := AsRoaringBitmap([]uint32{42})
+		if err := b.UnmarshalBinary(data); err == nil {
+			t.Errorf("%s: expected error, decoded %v", name, b.Slice())
+		}
+		if !slices.Equal(b.Slice(), []uint32{42}) {
+			t.Errorf("%s: receiver modified on error", name)
+		}
+	}
+}
+
+func TestRoaringUnmarshalCorruptedNoPanic(t *testing.T) {
+	r := rand.New(seeded(38))
+	b, _ := randomRoaring(r)
+	b.Add(1) // ensure at least one array container
+	valid, _ := b.MarshalBinary()
+
+	for trial := 0; trial < 2000; trial++ {
+		data := slices.Clone(valid)
+		for i := r.IntN(4) + 1; i > 0; i-- {
+			data[r.IntN(len(data))] = byte(r.Uint32())
+		}
+		if r.IntN(4) == 0 {
+			data = data[:r.IntN(len(data))]
+		}
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					t.Fatalf("UnmarshalBinary panicked on corrupted input: %v", p)
+				}
+			}()
+			_ = NewRoaringBitmap().UnmarshalBinary(data)
+		}()
+	}
+}
+
+var roaringBenchmarkSizes = []int{1e4, 1e6}
+
+// benchmarkValues returns n random uint32 values, clustered enough that
+// some containers become bitmaps.
+func benchmarkValues(n int) []uint32 {
+	r := rand.New(seeded(uint64(n)))
+	values := make([]uint32, n)
+	for i := range values {
+		values[i] = uint32(r.IntN(n * 4))
+	}
+	return values
+}
+
+func BenchmarkRoaringAdd(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		values := benchmarkValues(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				AsRoaringBitmap(values)
+			}
+		})
+	}
+}
+
+func BenchmarkSetAdd(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		values := benchmarkValues(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				AsSet(values)
+			}
+		})
+	}
+}
+
+func BenchmarkRoaringContains(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		rb := AsRoaringBitmap(benchmarkValues(n))
+		probes := benchmarkValues(1024)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rb.Contains(probes[i%len(probes)])
+			}
+		})
+	}
+}
+
+func BenchmarkSetContains(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		s := AsSet(benchmarkValues(n))
+		probes := benchmarkValues(1024)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Contains(probes[i%len(probes)])
+			}
+		})
+	}
+}
+
+func BenchmarkRoaringOrAnd(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		values := benchmarkValues(2 * n)
+		b1, b2 := AsRoaringBitmap(values[:n]), AsRoaringBitmap(values[n:])
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b1.Or(b2)
+				b1.And(b2)
+			}
+		})
+	}
+}
+
+func BenchmarkSetUnionIntersection(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		values := benchmarkValues(2 * n)
+		s1, s2 := AsSet(values[:n]), AsSet(values[n:])
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Union(s1, s2)
+				Intersection(s1, s2)
+			}
+		})
+	}
+}
+
+func BenchmarkRoaringCardinality(b *testing.B) {
+	for _, n := range roaringBenchmarkSizes {
+		rb := AsRoaringBitmap(benchmarkValues(n))
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rb.Cardinality()
+			}
+		})
+	}
+}
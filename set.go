@@ -1,6 +1,9 @@
 package collections
 
-import "fmt"
+import (
+	"fmt"
+	"math/rand/v2"
+)
 
 // Set is a implementation of a set using a Go hashmap.
 type Set[T comparable] map[T]o
@@ -66,6 +69,43 @@ func (s *Set[T]) Contains(t T) bool {
 	return ok
 }
 
+// IsSubset returns true if every element of this Set is in other.
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	for t := range *s {
+		if !other.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every element of other is in this Set.
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// IsDisjoint returns true if this Set and other have no elements in common.
+func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
+	small, large := s, other
+	if small.Size() > large.Size() {
+		small, large = large, small
+	}
+	for t := range *small {
+		if large.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if this Set and other contain exactly the same elements.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Size() == other.Size() && s.IsSubset(other)
+}
+
 // Basic (mutating) functions
 
 // Add adds t to this Set, if it is not already in the Set.
@@ -81,6 +121,130 @@ func (s *Set[T]) Remove(t T) bool {
 	return ret
 }
 
+// AddAll adds all the given elements to this Set.
+func (s *Set[T]) AddAll(ts ...T) {
+	for _, t := range ts {
+		s.Add(t)
+	}
+}
+
+// RemoveAll removes all the given elements from this Set.
+func (s *Set[T]) RemoveAll(ts ...T) {
+	for _, t := range ts {
+		s.Remove(t)
+	}
+}
+
+// RetainAll removes all elements from this Set except the given elements.
+func (s *Set[T]) RetainAll(ts ...T) {
+	s.IntersectWith(AsSet(ts))
+}
+
+// UnionWith adds all elements of the given Sets to this Set.
+func (s *Set[T]) UnionWith(sets ...*Set[T]) {
+	for _, set := range sets {
+		for t := range *set {
+			s.Add(t)
+		}
+	}
+}
+
+// IntersectWith removes all elements from this Set which are not in all of
+// the given Sets.
+func (s *Set[T]) IntersectWith(sets ...*Set[T]) {
+elementLoop:
+	for t := range *s {
+		for _, set := range sets {
+			if !set.Contains(t) {
+				s.Remove(t)
+				continue elementLoop
+			}
+		}
+	}
+}
+
+// DifferenceWith removes all elements from this Set which are in other.
+func (s *Set[T]) DifferenceWith(other *Set[T]) {
+	for t := range *other {
+		s.Remove(t)
+	}
+}
+
+// SymmetricDifferenceWith modifies this Set so that it contains all elements
+// which are in exactly one of this Set and other.
+func (s *Set[T]) SymmetricDifferenceWith(other *Set[T]) {
+	for t := range *other {
+		if !s.Remove(t) {
+			s.Add(t)
+		}
+	}
+}
+
+// Random sampling methods
+
+// RandomElement returns an element of this Set chosen uniformly at random
+// using the given source. If src is nil, the global random source is used.
+// It returns an error if the Set is empty.
+//
+// Since Go does not define an iteration order for maps, the element chosen
+// is not reproducible for a given source; use RandomElementOrdered for
+// deterministic sampling.
+func (s *Set[T]) RandomElement(src rand.Source) (t T, err error) {
+	if s.IsEmpty() {
+		err = errSetEmpty
+		return
+	}
+
+	n := randFrom(src).IntN(s.Size())
+	for t = range *s {
+		if n == 0 {
+			break
+		}
+		n--
+	}
+	return
+}
+
+// RandomElementOrdered returns an element of this Set chosen uniformly at
+// random using the given source. The elements are first sorted using order,
+// which must be a strict total order, so the element chosen is reproducible
+// for a seeded source. If src is nil, the global random source is used.
+// It returns an error if the Set is empty.
+func (s *Set[T]) RandomElementOrdered(order func(T, T) bool, src rand.Source) (t T, err error) {
+	if s.IsEmpty() {
+		err = errSetEmpty
+		return
+	}
+
+	elems := AsList(s.Slice())
+	elems.Sort(order)
+	return elems.At(randFrom(src).IntN(elems.Size())), nil
+}
+
+// Pop removes an element chosen uniformly at random from this Set, and
+// returns it. If src is nil, the global random source is used. It returns an
+// error if the Set is empty.
+//
+// As with RandomElement, the element chosen is not reproducible for a given
+// source; use PopOrdered for deterministic sampling.
+func (s *Set[T]) Pop(src rand.Source) (t T, err error) {
+	t, err = s.RandomElement(src)
+	if err == nil {
+		s.Remove(t)
+	}
+	return
+}
+
+// PopOrdered removes an element chosen as in RandomElementOrdered from this
+// Set, and returns it. It returns an error if the Set is empty.
+func (s *Set[T]) PopOrdered(order func(T, T) bool, src rand.Source) (t T, err error) {
+	t, err = s.RandomElementOrdered(order, src)
+	if err == nil {
+		s.Remove(t)
+	}
+	return
+}
+
 // Copying functions
 
 // Copy returns a copy of the given Set.
@@ -92,6 +256,48 @@ func (s *Set[T]) Copy() *Set[T] {
 	return cp
 }
 
+// CopyDeep returns a deep copy of the given Set, in which each element is
+// copied using clone. If clone is nil, elements implementing Cloner[T] are
+// copied using their Clone method, and other elements are copied as-is.
+// If clone maps distinct elements to equal ones, the copy will be smaller
+// than the original.
+func (s *Set[T]) CopyDeep(clone func(t T) T) *Set[T] {
+	clone = cloneFunc(clone)
+	cp := NewSet[T](s.Size())
+	for t := range *s {
+		cp.Add(clone(t))
+	}
+	return cp
+}
+
+// Functional methods
+
+// Filter returns a new Set containing only the elements t in this Set such
+// that f(t) == true.
+func (s *Set[T]) Filter(f func(T) bool) *Set[T] {
+	fSet := NewSet[T](0)
+	for t := range *s {
+		if f(t) {
+			fSet.Add(t)
+		}
+	}
+	return fSet
+}
+
+// Partition splits this Set into two new Sets: in contains the elements t
+// such that f(t) == true, and out contains the rest.
+func (s *Set[T]) Partition(f func(T) bool) (in, out *Set[T]) {
+	in, out = NewSet[T](0), NewSet[T](0)
+	for t := range *s {
+		if f(t) {
+			in.Add(t)
+		} else {
+			out.Add(t)
+		}
+	}
+	return
+}
+
 // Union returns the Set of all elements which are in any of the given Sets.
 func Union[T comparable](sets ...*Set[T]) *Set[T] {
 	union := NewSet[T](0)
@@ -138,4 +344,64 @@ func SymmetricDifference[T comparable](s1, s2 *Set[T]) *Set[T] {
 	return Union(Difference(s1, s2), Difference(s2, s1))
 }
 
-// TODO: write tests verifying that set theory laws hold
+// Pair is an ordered pair of values.
+type Pair[T, U any] struct {
+	First  T
+	Second U
+}
+
+// CartesianProduct returns the Set of all Pairs (t, u) where t is in s1 and
+// u is in s2.
+func CartesianProduct[T, U comparable](s1 *Set[T], s2 *Set[U]) *Set[Pair[T, U]] {
+	product := NewSet[Pair[T, U]](s1.Size() * s2.Size())
+	for t := range *s1 {
+		for u := range *s2 {
+			product.Add(Pair[T, U]{t, u})
+		}
+	}
+	return product
+}
+
+type powerSetIterator[T comparable] struct {
+	elems []T
+	// include[i] is true if elems[i] is in the next subset.
+	include []bool
+	done    bool
+}
+
+func (i *powerSetIterator[T]) HasNext() bool {
+	return !i.done
+}
+
+func (i *powerSetIterator[T]) Next() *Set[T] {
+	subset := NewSet[T](0)
+	for j, t := range i.elems {
+		if i.include[j] {
+			subset.Add(t)
+		}
+	}
+
+	// Advance include like a binary counter.
+	i.done = true
+	for j := range i.include {
+		i.include[j] = !i.include[j]
+		if i.include[j] {
+			i.done = false
+			break
+		}
+	}
+	return subset
+}
+
+// PowerSet returns an Iterator over all subsets of s, starting with the empty
+// Set. The subsets are generated lazily, so only one is held in memory at a
+// time. Changes made to s during iteration do not affect the Iterator.
+func PowerSet[T comparable](s *Set[T]) Iterator[*Set[T]] {
+	return &powerSetIterator[T]{
+		elems:   s.Slice(),
+		include: make([]bool, s.Size()),
+	}
+}
+
+// Errors
+var errSetEmpty = fmt.Errorf("set is empty")
@@ -0,0 +1,224 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+const setLawUniverse = 16
+
+// setLawTrials calls f with many seeded triples of random subsets of
+// [0, setLawUniverse), and the universe itself.
+func setLawTrials(t *testing.T, f func(a, b, c, u *Set[int])) {
+	r := rand.New(seeded(32))
+	u := NewSet[int](setLawUniverse)
+	for i := 0; i < setLawUniverse; i++ {
+		u.Add(i)
+	}
+	randomSet := func() *Set[int] {
+		s := NewSet[int](0)
+		density := r.Float64()
+		for i := 0; i < setLawUniverse; i++ {
+			if r.Float64() < density {
+				s.Add(i)
+			}
+		}
+		return s
+	}
+
+	for trial := 0; trial < 500; trial++ {
+		a, b, c := randomSet(), randomSet(), randomSet()
+		// Make subset relations common.
+		switch trial % 3 {
+		case 1:
+			b.UnionWith(a)
+		case 2:
+			b = a.Copy()
+		}
+		f(a, b, c, u)
+		if t.Failed() {
+			t.Fatalf("failed for a=%v, b=%v, c=%v", a.Slice(), b.Slice(), c.Slice())
+		}
+	}
+}
+
+func assertSetEqual(t *testing.T, law string, got, want *Set[int]) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("%s: got %v, want %v", law, got.Slice(), want.Slice())
+	}
+}
+
+func TestSetCommutativity(t *testing.T) {
+	setLawTrials(t, func(a, b, _, _ *Set[int]) {
+		assertSetEqual(t, "A∪B = B∪A", Union(a, b), Union(b, a))
+		assertSetEqual(t, "A∩B = B∩A", Intersection(a, b), Intersection(b, a))
+		assertSetEqual(t, "AΔB = BΔA", SymmetricDifference(a, b), SymmetricDifference(b, a))
+	})
+}
+
+func TestSetAssociativity(t *testing.T) {
+	setLawTrials(t, func(a, b, c, _ *Set[int]) {
+		assertSetEqual(t, "(A∪B)∪C = A∪(B∪C)", Union(Union(a, b), c), Union(a, Union(b, c)))
+		assertSetEqual(t, "(A∩B)∩C = A∩(B∩C)",
+			Intersection(Intersection(a, b), c), Intersection(a, Intersection(b, c)))
+		assertSetEqual(t, "(AΔB)ΔC = AΔ(BΔC)",
+			SymmetricDifference(SymmetricDifference(a, b), c),
+			SymmetricDifference(a, SymmetricDifference(b, c)))
+		assertSetEqual(t, "variadic Union", Union(a, b, c), Union(Union(a, b), c))
+		assertSetEqual(t, "variadic Intersection", Intersection(a, b, c), Intersection(Intersection(a, b), c))
+	})
+}
+
+func TestSetDistributivity(t *testing.T) {
+	setLawTrials(t, func(a, b, c, _ *Set[int]) {
+		assertSetEqual(t, "A∩(B∪C) = (A∩B)∪(A∩C)",
+			Intersection(a, Union(b, c)), Union(Intersection(a, b), Intersection(a, c)))
+		assertSetEqual(t, "A∪(B∩C) = (A∪B)∩(A∪C)",
+			Union(a, Intersection(b, c)), Intersection(Union(a, b), Union(a, c)))
+	})
+}
+
+func TestSetDeMorgan(t *testing.T) {
+	setLawTrials(t, func(a, b, _, u *Set[int]) {
+		assertSetEqual(t, "U\\(A∪B) = (U\\A)∩(U\\B)",
+			Difference(u, Union(a, b)), Intersection(Difference(u, a), Difference(u, b)))
+		assertSetEqual(t, "U\\(A∩B) = (U\\A)∪(U\\B)",
+			Difference(u, Intersection(a, b)), Union(Difference(u, a), Difference(u, b)))
+		assertSetEqual(t, "AΔB = (A\\B)∪(B\\A)",
+			SymmetricDifference(a, b), Union(Difference(a, b), Difference(b, a)))
+	})
+}
+
+func TestSetRelations(t *testing.T) {
+	setLawTrials(t, func(a, b, _, u *Set[int]) {
+		subset := a.IsSubset(b)
+		if subset != Union(a, b).Equal(b) || subset != Intersection(a, b).Equal(a) {
+			t.Errorf("IsSubset = %v disagrees with A∪B = B and A∩B = A", subset)
+		}
+		if subset != Difference(a, b).IsEmpty() {
+			t.Errorf("IsSubset = %v disagrees with A\\B = ∅", subset)
+		}
+		if a.IsSuperset(b) != b.IsSubset(a) {
+			t.Errorf("IsSuperset disagrees with IsSubset")
+		}
+		if a.Equal(b) != (a.IsSubset(b) && b.IsSubset(a)) {
+			t.Errorf("Equal disagrees with mutual IsSubset")
+		}
+		if a.IsDisjoint(b) != Intersection(a, b).IsEmpty() || a.IsDisjoint(b) != b.IsDisjoint(a) {
+			t.Errorf("IsDisjoint disagrees with A∩B = ∅")
+		}
+		if !a.IsSubset(Union(a, b)) || !Intersection(a, b).IsSubset(a) || !a.IsSubset(u) {
+			t.Errorf("subset of union/superset of intersection violated")
+		}
+		if !Difference(u, a).IsDisjoint(a) {
+			t.Errorf("complement of A is not disjoint from A")
+		}
+	})
+}
+
+func TestSetInPlaceParity(t *testing.T) {
+	setLawTrials(t, func(a, b, c, _ *Set[int]) {
+		s := a.Copy()
+		s.UnionWith(b, c)
+		assertSetEqual(t, "UnionWith", s, Union(a, b, c))
+
+		s = a.Copy()
+		s.IntersectWith(b, c)
+		assertSetEqual(t, "IntersectWith", s, Intersection(a, b, c))
+
+		s = a.Copy()
+		s.DifferenceWith(b)
+		assertSetEqual(t, "DifferenceWith", s, Difference(a, b))
+
+		s = a.Copy()
+		s.SymmetricDifferenceWith(b)
+		assertSetEqual(t, "SymmetricDifferenceWith", s, SymmetricDifference(a, b))
+
+		s = a.Copy()
+		s.AddAll(b.Slice()...)
+		assertSetEqual(t, "AddAll", s, Union(a, b))
+
+		s = a.Copy()
+		s.RemoveAll(b.Slice()...)
+		assertSetEqual(t, "RemoveAll", s, Difference(a, b))
+
+		s = a.Copy()
+		s.RetainAll(b.Slice()...)
+		assertSetEqual(t, "RetainAll", s, Intersection(a, b))
+
+		// The in-place operations must not modify their arguments.
+		aBefore, bBefore := a.Copy(), b.Copy()
+		s = c.Copy()
+		s.UnionWith(a)
+		s.IntersectWith(b)
+		s.DifferenceWith(a)
+		s.SymmetricDifferenceWith(b)
+		if !a.Equal(aBefore) || !b.Equal(bBefore) {
+			t.Errorf("in-place operation modified its argument")
+		}
+	})
+}
+
+func TestSetFilterPartition(t *testing.T) {
+	even := func(x int) bool { return x%2 == 0 }
+	setLawTrials(t, func(a, _, _, _ *Set[int]) {
+		in, out := a.Partition(even)
+		assertSetEqual(t, "Filter = Partition in", a.Filter(even), in)
+		assertSetEqual(t, "in ∪ out = A", Union(in, out), a)
+		if !in.IsDisjoint(out) {
+			t.Errorf("Partition halves overlap")
+		}
+		for _, x := range in.Slice() {
+			if !even(x) {
+				t.Errorf("Partition put %d in the wrong half", x)
+			}
+		}
+	})
+}
+
+func TestCartesianProduct(t *testing.T) {
+	setLawTrials(t, func(a, b, _, _ *Set[int]) {
+		p := CartesianProduct(a, b)
+		if p.Size() != a.Size()*b.Size() {
+			t.Errorf("|A×B| = %d, want %d", p.Size(), a.Size()*b.Size())
+		}
+		for _, pair := range p.Slice() {
+			if !a.Contains(pair.First) || !b.Contains(pair.Second) {
+				t.Errorf("unexpected pair %v", pair)
+			}
+		}
+	})
+}
+
+func TestPowerSet(t *testing.T) {
+	for n := 0; n <= 10; n++ {
+		s := NewSet[int](n)
+		for i := 0; i < n; i++ {
+			s.Add(i)
+		}
+
+		// Encode each subset as a bitmask to check they are distinct.
+		seen := NewSet[int](1 << n)
+		it := PowerSet(s)
+		first := true
+		for it.HasNext() {
+			sub := it.Next()
+			if first && !sub.IsEmpty() {
+				t.Errorf("n=%d: first subset %v is not empty", n, sub.Slice())
+			}
+			first = false
+			if !sub.IsSubset(s) {
+				t.Fatalf("n=%d: %v is not a subset", n, sub.Slice())
+			}
+			mask := 0
+			for _, x := range sub.Slice() {
+				mask |= 1 << x
+			}
+			seen.Add(mask)
+		}
+		if seen.Size() != 1<<n {
+			t.Errorf("n=%d: power set has %d distinct subsets, want %d", n, seen.Size(), 1<<n)
+		}
+	}
+}
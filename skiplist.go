@@ -0,0 +1,318 @@
+package collections
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+// SkipList is an implementation of an ordered map using a skip list. Keys are
+// ordered by the less function given to the constructor, in the same way as
+// Map.Iterate orders keys, and lookups, insertions and removals take
+// O(log n) time on average.
+//
+// A SkipList made with NewConcurrentSkipList is safe for concurrent use by
+// multiple goroutines. Reads (e.g. Get, Floor, Range) take no lock: they
+// follow next pointers which writers update atomically, so they never block
+// and are never blocked by writes. Writes are serialised by a mutex. A
+// SkipList made with NewSkipList does no locking.
+type SkipList[K, V any] struct {
+	head  skipNode[K, V]
+	level atomic.Int32 // number of levels in use
+	size  atomic.Int64
+	less  func(K, K) bool
+	rand  *rand.Rand
+	// mu is nil if this SkipList is not safe for concurrent use.
+	mu *sync.Mutex
+}
+
+// maxSkipLevel is the maximum number of levels in a SkipList, which is enough
+// for 2^32 elements.
+const maxSkipLevel = 32
+
+// skipNode is a node in a SkipList. Its key never changes, and its value and
+// next pointers are accessed atomically, so that readers need no lock.
+//
+// Writers link a new node in from the bottom level up, after setting its own
+// next pointers, so a reader sees either the old list or a list containing
+// the fully initialised node. Writers unlink a removed node without changing
+// its next pointers, so a reader positioned on it can still move forward.
+type skipNode[K, V any] struct {
+	key   K
+	value atomic.Pointer[V]
+	// next[i] is the next node at level i.
+	next []atomic.Pointer[skipNode[K, V]]
+}
+
+// Constructors
+
+// NewSkipList makes a new empty SkipList, using less to order keys. The
+// levels of new nodes are chosen randomly using src; pass a seeded source for
+// a deterministic structure, or nil to use the global random source.
+func NewSkipList[K, V any](less func(K, K) bool, src rand.Source) *SkipList[K, V] {
+	s := &SkipList[K, V]{
+		less: less,
+		rand: randFrom(src),
+	}
+	s.head.next = make([]atomic.Pointer[skipNode[K, V]], maxSkipLevel)
+	s.level.Store(1)
+	return s
+}
+
+// NewConcurrentSkipList makes a new empty SkipList which is safe for
+// concurrent use, using less to order keys. The levels of new nodes are
+// chosen randomly using src, or the global random source if src is nil.
+func NewConcurrentSkipList[K, V any](less func(K, K) bool, src rand.Source) *SkipList[K, V] {
+	s := NewSkipList[K, V](less, src)
+	s.mu = &sync.Mutex{}
+	return s
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of entries in this SkipList.
+func (s *SkipList[K, V]) Size() int {
+	return int(s.size.Load())
+}
+
+// IsEmpty returns true if this SkipList is empty.
+func (s *SkipList[K, V]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Contains returns true if the given key is in the SkipList.
+func (s *SkipList[K, V]) Contains(k K) bool {
+	_, err := s.Get(k)
+	return err == nil
+}
+
+// Get returns the value associated with k. If k is not a key in this
+// SkipList, Get returns an error.
+func (s *SkipList[K, V]) Get(k K) (v V, err error) {
+	n := s.ceiling(k)
+	if n == nil || s.less(k, n.key) {
+		err = errKeyNotFound(k)
+		return
+	}
+	return *n.value.Load(), nil
+}
+
+// Floor returns the entry with the largest key less than or equal to k.
+// It returns an error if there is no such entry.
+func (s *SkipList[K, V]) Floor(k K) (key K, v V, err error) {
+	n := s.lastBefore(k, true)
+	if n == &s.head {
+		err = errNoSuchKey("less than or equal to", k)
+		return
+	}
+	return n.key, *n.value.Load(), nil
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to
+// k. It returns an error if there is no such entry.
+func (s *SkipList[K, V]) Ceiling(k K) (key K, v V, err error) {
+	n := s.ceiling(k)
+	if n == nil {
+		err = errNoSuchKey("greater than or equal to", k)
+		return
+	}
+	return n.key, *n.value.Load(), nil
+}
+
+// First returns the entry with the smallest key. It returns an error if the
+// SkipList is empty.
+func (s *SkipList[K, V]) First() (k K, v V, err error) {
+	n := s.head.next[0].Load()
+	if n == nil {
+		err = errSkipListEmpty
+		return
+	}
+	return n.key, *n.value.Load(), nil
+}
+
+// Last returns the entry with the largest key. It returns an error if the
+// SkipList is empty.
+func (s *SkipList[K, V]) Last() (k K, v V, err error) {
+	n := &s.head
+	for i := s.topLevel(); i >= 0; i-- {
+		for next := n.next[i].Load(); next != nil; next = n.next[i].Load() {
+			n = next
+		}
+	}
+	if n == &s.head {
+		err = errSkipListEmpty
+		return
+	}
+	return n.key, *n.value.Load(), nil
+}
+
+// Basic (mutating) functions
+
+// Set adds the given key-value pair to this SkipList. If there is already a
+// value associated with k, it will be overwritten.
+func (s *SkipList[K, V]) Set(k K, v V) {
+	s.lock()
+	defer s.unlock()
+
+	var update [maxSkipLevel]*skipNode[K, V]
+	n := s.findPredecessors(k, &update)
+	if n = n.next[0].Load(); n != nil && !s.less(k, n.key) {
+		n.value.Store(&v)
+		return
+	}
+
+	level := s.randomLevel()
+	for i := int(s.level.Load()); i < level; i++ {
+		update[i] = &s.head
+	}
+
+	n = &skipNode[K, V]{key: k, next: make([]atomic.Pointer[skipNode[K, V]], level)}
+	n.value.Store(&v)
+	for i := 0; i < level; i++ {
+		n.next[i].Store(update[i].next[i].Load())
+	}
+	// Link from the bottom up, so a reader which finds n at some level will
+	// also find it at every level below.
+	for i := 0; i < level; i++ {
+		update[i].next[i].Store(n)
+	}
+	if level > int(s.level.Load()) {
+		s.level.Store(int32(level))
+	}
+	s.size.Add(1)
+}
+
+// Remove removes k and its associated value from this SkipList. It returns
+// false if k was not in the SkipList to begin with, and returns true if k was
+// removed.
+func (s *SkipList[K, V]) Remove(k K) bool {
+	s.lock()
+	defer s.unlock()
+
+	var update [maxSkipLevel]*skipNode[K, V]
+	n := s.findPredecessors(k, &update).next[0].Load()
+	if n == nil || s.less(k, n.key) {
+		return false
+	}
+
+	// Unlink from the top down, the reverse of Set. n's own next pointers
+	// are left alone for any reader positioned on n.
+	for i := len(n.next) - 1; i >= 0; i-- {
+		update[i].next[i].Store(n.next[i].Load())
+	}
+	level := s.level.Load()
+	for level > 1 && s.head.next[level-1].Load() == nil {
+		level--
+	}
+	s.level.Store(level)
+	s.size.Add(-1)
+	return true
+}
+
+// Clear removes all entries from this SkipList.
+func (s *SkipList[K, V]) Clear() {
+	s.lock()
+	defer s.unlock()
+
+	for i := range s.head.next {
+		s.head.next[i].Store(nil)
+	}
+	s.level.Store(1)
+	s.size.Store(0)
+}
+
+// Iteration
+
+// Range returns an Iterator2 over the entries of this SkipList with keys in
+// the half-open interval [low, high), in increasing order of key.
+// The entries are copied when Range is called, so the SkipList may be
+// modified during iteration. In a concurrent SkipList, writes made while
+// Range is copying may or may not be reflected.
+func (s *SkipList[K, V]) Range(low, high K) Iterator2[K, V] {
+	entries := NewList[Entry[K, V]](0)
+	for n := s.ceiling(low); n != nil && s.less(n.key, high); n = n.next[0].Load() {
+		entries.Append(Entry[K, V]{n.key, *n.value.Load()})
+	}
+	return &entryIterator[K, V]{entries: entries}
+}
+
+// Iterate returns an Iterator2 over all entries of this SkipList, in
+// increasing order of key. The entries are copied when Iterate is called, so
+// the SkipList may be modified during iteration. In a concurrent SkipList,
+// writes made while Iterate is copying may or may not be reflected.
+func (s *SkipList[K, V]) Iterate() Iterator2[K, V] {
+	entries := NewList[Entry[K, V]](s.Size())
+	for n := s.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+		entries.Append(Entry[K, V]{n.key, *n.value.Load()})
+	}
+	return &entryIterator[K, V]{entries: entries}
+}
+
+// Internal methods
+
+// findPredecessors sets update[i] to the last node at level i with key less
+// than k, and returns update[0].
+func (s *SkipList[K, V]) findPredecessors(k K, update *[maxSkipLevel]*skipNode[K, V]) *skipNode[K, V] {
+	n := &s.head
+	for i := s.topLevel(); i >= 0; i-- {
+		for next := n.next[i].Load(); next != nil && s.less(next.key, k); next = n.next[i].Load() {
+			n = next
+		}
+		update[i] = n
+	}
+	return n
+}
+
+// lastBefore returns the last node with key less than k (or less than or
+// equal to k, if inclusive is true). It returns &s.head if there is none.
+func (s *SkipList[K, V]) lastBefore(k K, inclusive bool) *skipNode[K, V] {
+	n := &s.head
+	for i := s.topLevel(); i >= 0; i-- {
+		for next := n.next[i].Load(); next != nil; next = n.next[i].Load() {
+			if s.less(next.key, k) || (inclusive && !s.less(k, next.key)) {
+				n = next
+			} else {
+				break
+			}
+		}
+	}
+	return n
+}
+
+// ceiling returns the first node with key greater than or equal to k, or nil
+// if there is none.
+func (s *SkipList[K, V]) ceiling(k K) *skipNode[K, V] {
+	return s.lastBefore(k, false).next[0].Load()
+}
+
+// topLevel returns the index of the highest level in use.
+func (s *SkipList[K, V]) topLevel() int {
+	return int(s.level.Load()) - 1
+}
+
+// randomLevel returns a random level between 1 and maxSkipLevel, where each
+// level is half as likely as the previous one.
+func (s *SkipList[K, V]) randomLevel() int {
+	return min(1+bits.TrailingZeros32(uint32(s.rand.Uint64())|1<<(maxSkipLevel-1)), maxSkipLevel)
+}
+
+func (s *SkipList[K, V]) lock() {
+	if s.mu != nil {
+		s.mu.Lock()
+	}
+}
+
+func (s *SkipList[K, V]) unlock() {
+	if s.mu != nil {
+		s.mu.Unlock()
+	}
+}
+
+// Errors
+var errSkipListEmpty = fmt.Errorf("skip list is empty")
+
+func errNoSuchKey(relation string, k any) error {
+	return fmt.Errorf("no key %s %v in SkipList", relation, k)
+}
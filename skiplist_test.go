@@ -0,0 +1,255 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// checkSkipList checks the structure of s against the sorted keys of ref.
+func checkSkipList(t *testing.T, s *SkipList[int, int], ref map[int]int) {
+	t.Helper()
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	if s.Size() != len(keys) {
+		t.Fatalf("Size() = %d, want %d", s.Size(), len(keys))
+	}
+	i := 0
+	for it := s.Iterate(); it.HasNext(); i++ {
+		k, v := it.Next()
+		if i >= len(keys) || k != keys[i] || v != ref[k] {
+			t.Fatalf("Iterate() gave (%d, %d) at position %d", k, v, i)
+		}
+	}
+	if i != len(keys) {
+		t.Fatalf("Iterate() gave %d entries, want %d", i, len(keys))
+	}
+
+	// Each level must be a sorted sublist of the level below.
+	for level := 1; level < int(s.level.Load()); level++ {
+		below := &s.head
+		for n := s.head.next[level].Load(); n != nil; n = n.next[level].Load() {
+			for below != n {
+				if below = below.next[level-1].Load(); below == nil {
+					t.Fatalf("key %d at level %d is missing from level %d", n.key, level, level-1)
+				}
+			}
+		}
+	}
+	if top := s.level.Load(); top > 1 && s.head.next[top-1].Load() == nil {
+		t.Fatalf("top level %d is empty", top)
+	}
+}
+
+func TestSkipListAgainstMap(t *testing.T) {
+	r := rand.New(seeded(39))
+	s := NewSkipList[int, int](intLess, seeded(40))
+	ref := map[int]int{}
+
+	for i := 0; i < 5000; i++ {
+		k := r.IntN(500)
+		switch r.IntN(4) {
+		case 0:
+			_, want := ref[k]
+			if got := s.Remove(k); got != want {
+				t.Fatalf("Remove(%d) = %v, want %v", k, got, want)
+			}
+			delete(ref, k)
+		default:
+			s.Set(k, i)
+			ref[k] = i
+		}
+		if i%2000 == 1999 {
+			s.Clear()
+			clear(ref)
+		}
+
+		q := r.IntN(520) - 10
+		want, ok := ref[q]
+		if got, err := s.Get(q); (err == nil) != ok || got != want {
+			t.Fatalf("Get(%d) = %d, %v; want %d, %v", q, got, err, want, ok)
+		}
+		if s.Contains(q) != ok {
+			t.Fatalf("Contains(%d) = %v, want %v", q, !ok, ok)
+		}
+		if i%100 == 0 {
+			checkSkipList(t, s, ref)
+		}
+	}
+	checkSkipList(t, s, ref)
+}
+
+func TestSkipListOrderedQueries(t *testing.T) {
+	r := rand.New(seeded(41))
+	s := NewSkipList[int, int](intLess, seeded(42))
+	if _, _, err := s.First(); err == nil {
+		t.Errorf("expected error from First on an empty SkipList")
+	}
+	if _, _, err := s.Last(); err == nil {
+		t.Errorf("expected error from Last on an empty SkipList")
+	}
+
+	var keys []int
+	for i := 0; i < 200; i++ {
+		k := r.IntN(1000)
+		s.Set(k, -k)
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	if k, v, err := s.First(); err != nil || k != keys[0] || v != -k {
+		t.Errorf("First() = %d, %d, %v; want %d", k, v, err, keys[0])
+	}
+	if k, v, err := s.Last(); err != nil || k != keys[len(keys)-1] || v != -k {
+		t.Errorf("Last() = %d, %d, %v; want %d", k, v, err, keys[len(keys)-1])
+	}
+
+	for q := -5; q < 1005; q++ {
+		i, found := slices.BinarySearch(keys, q)
+
+		k, v, err := s.Ceiling(q)
+		if i == len(keys) {
+			if err == nil {
+				t.Fatalf("Ceiling(%d) = %d, want error", q, k)
+			}
+		} else if err != nil || k != keys[i] || v != -k {
+			t.Fatalf("Ceiling(%d) = %d, %v; want %d", q, k, err, keys[i])
+		}
+
+		j := i - 1
+		if found {
+			j = i
+		}
+		k, v, err = s.Floor(q)
+		if j < 0 {
+			if err == nil {
+				t.Fatalf("Floor(%d) = %d, want error", q, k)
+			}
+		} else if err != nil || k != keys[j] || v != -k {
+			t.Fatalf("Floor(%d) = %d, %v; want %d", q, k, err, keys[j])
+		}
+	}
+
+	for trial := 0; trial < 100; trial++ {
+		low := r.IntN(1100) - 50
+		high := low + r.IntN(300)
+		lo, _ := slices.BinarySearch(keys, low)
+		hi, _ := slices.BinarySearch(keys, high)
+		var got []int
+		for it := s.Range(low, high); it.HasNext(); {
+			k, _ := it.Next()
+			got = append(got, k)
+		}
+		if want := keys[lo:hi]; !slices.Equal(got, want) {
+			t.Fatalf("Range(%d, %d) = %v, want %v", low, high, got, want)
+		}
+	}
+}
+
+func TestSkipListDeterministic(t *testing.T) {
+	levels := func() []int {
+		s := NewSkipList[int, int](intLess, seeded(43))
+		for i := 0; i < 100; i++ {
+			s.Set(i, i)
+		}
+		var ls []int
+		for n := s.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			ls = append(ls, len(n.next))
+		}
+		return ls
+	}
+	if a, b := levels(), levels(); !slices.Equal(a, b) {
+		t.Errorf("SkipLists built with the same seed have different structure")
+	}
+}
+
+// TestConcurrentSkipList runs readers alongside writers; run with -race.
+// Writers only touch keys in their own residue class, so each writer can
+// check its own keys, and readers check that every value they see matches
+// its key.
+func TestConcurrentSkipList(t *testing.T) {
+	const writers, readers, keys, ops = 4, 4, 400, 2000
+	s := NewConcurrentSkipList[int, int](intLess, nil)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(seeded(uint64(w)))
+			present := map[int]bool{}
+			for i := 0; i < ops; i++ {
+				k := r.IntN(keys/writers)*writers + w
+				if r.IntN(3) == 0 {
+					if s.Remove(k) != present[k] {
+						t.Errorf("writer %d: Remove(%d) disagrees with its own writes", w, k)
+						return
+					}
+					delete(present, k)
+				} else {
+					s.Set(k, 10*k)
+					present[k] = true
+				}
+				if s.Contains(k) != present[k] {
+					t.Errorf("writer %d: Contains(%d) disagrees with its own writes", w, k)
+					return
+				}
+			}
+		}(w)
+	}
+
+	var readerWG sync.WaitGroup
+	for rd := 0; rd < readers; rd++ {
+		readerWG.Add(1)
+		go func(rd int) {
+			defer readerWG.Done()
+			r := rand.New(seeded(uint64(100 + rd)))
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				k := r.IntN(keys)
+				if v, err := s.Get(k); err == nil && v != 10*k {
+					t.Errorf("Get(%d) = %d", k, v)
+				}
+				if fk, fv, err := s.Floor(k); err == nil && (fk > k || fv != 10*fk) {
+					t.Errorf("Floor(%d) = %d, %d", k, fk, fv)
+				}
+				if ck, cv, err := s.Ceiling(k); err == nil && (ck < k || cv != 10*ck) {
+					t.Errorf("Ceiling(%d) = %d, %d", k, ck, cv)
+				}
+				prev := -1
+				for it := s.Range(k, k+50); it.HasNext(); {
+					rk, rv := it.Next()
+					if rk <= prev || rk < k || rk >= k+50 || rv != 10*rk {
+						t.Errorf("Range(%d, %d) gave (%d, %d) after %d", k, k+50, rk, rv, prev)
+					}
+					prev = rk
+				}
+				s.Size()
+				s.First()
+				s.Last()
+			}
+		}(rd)
+	}
+
+	wg.Wait()
+	close(done)
+	readerWG.Wait()
+
+	ref := map[int]int{}
+	for it := s.Iterate(); it.HasNext(); {
+		k, v := it.Next()
+		ref[k] = v
+	}
+	checkSkipList(t, s, ref)
+}
@@ -0,0 +1,336 @@
+package collections
+
+import (
+	"fmt"
+	"time"
+)
+
+// Number is a constraint permitting any integer or floating-point type.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// windowBounds determines which samples belong to a sliding window: either
+// the last size samples, or (if clock is non-nil) the samples added within
+// the last span.
+type windowBounds struct {
+	size  int
+	span  time.Duration
+	clock Clock
+}
+
+func countBounds(size int) windowBounds {
+	if size <= 0 {
+		panic(fmt.Sprintf("non-positive window size %d", size))
+	}
+	return windowBounds{size: size}
+}
+
+func timeBounds(span time.Duration, clock Clock) windowBounds {
+	if span <= 0 {
+		panic(fmt.Sprintf("non-positive window span %v", span))
+	}
+	return windowBounds{span: span, clock: clockOrDefault(clock)}
+}
+
+// now returns the current time, or the zero time for a count-based window.
+func (b windowBounds) now() time.Time {
+	if b.clock == nil {
+		return time.Time{}
+	}
+	return b.clock.Now()
+}
+
+// evict returns true if the oldest sample, added at the given time, should
+// be evicted from a window containing count samples.
+func (b windowBounds) evict(count int, oldest, now time.Time) bool {
+	if b.clock == nil {
+		return count > b.size
+	}
+	return !oldest.After(now.Add(-b.span))
+}
+
+type windowSample[T any] struct {
+	value T
+	added time.Time
+	seq   int
+}
+
+// SlidingWindow maintains the sum, minimum, maximum and mean of the most
+// recent samples added to it, in amortised O(1) time per sample. The window
+// holds either a fixed number of samples, or the samples added within a
+// fixed duration.
+//
+// The sum is maintained incrementally, so for floating-point types it may
+// accumulate rounding error over many samples.
+type SlidingWindow[T Number] struct {
+	bounds  windowBounds
+	samples *Queue[windowSample[T]]
+	// minDeque and maxDeque hold the samples which may still become the
+	// window's minimum or maximum, in increasing and decreasing order of
+	// value respectively.
+	minDeque *Queue[windowSample[T]]
+	maxDeque *Queue[windowSample[T]]
+	sum      T
+	seq      int
+}
+
+// Constructors
+
+// NewSlidingWindow makes a new SlidingWindow holding the last size samples.
+// It panics if size is not positive.
+func NewSlidingWindow[T Number](size int) *SlidingWindow[T] {
+	return newSlidingWindow[T](countBounds(size))
+}
+
+// NewTimeSlidingWindow makes a new SlidingWindow holding the samples added
+// within the last span, as measured by the given clock. If clock is nil, the
+// system clock is used.
+// It panics if span is not positive.
+func NewTimeSlidingWindow[T Number](span time.Duration, clock Clock) *SlidingWindow[T] {
+	return newSlidingWindow[T](timeBounds(span, clock))
+}
+
+func newSlidingWindow[T Number](bounds windowBounds) *SlidingWindow[T] {
+	return &SlidingWindow[T]{
+		bounds:   bounds,
+		samples:  NewQueue[windowSample[T]](bounds.size),
+		minDeque: NewQueue[windowSample[T]](0),
+		maxDeque: NewQueue[windowSample[T]](0),
+	}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of samples currently in this SlidingWindow.
+func (w *SlidingWindow[T]) Size() int {
+	w.expire(w.bounds.now())
+	return w.samples.Size()
+}
+
+// IsEmpty returns true if this SlidingWindow contains no samples.
+func (w *SlidingWindow[T]) IsEmpty() bool {
+	return w.Size() == 0
+}
+
+// Sum returns the sum of the samples in this SlidingWindow, or 0 if it is
+// empty.
+func (w *SlidingWindow[T]) Sum() T {
+	w.expire(w.bounds.now())
+	return w.sum
+}
+
+// Mean returns the mean of the samples in this SlidingWindow.
+// It returns an error if the window is empty.
+func (w *SlidingWindow[T]) Mean() (float64, error) {
+	w.expire(w.bounds.now())
+	if w.samples.IsEmpty() {
+		return 0, errWindowEmpty
+	}
+	return float64(w.sum) / float64(w.samples.Size()), nil
+}
+
+// Min returns the smallest sample in this SlidingWindow.
+// It returns an error if the window is empty.
+func (w *SlidingWindow[T]) Min() (t T, err error) {
+	w.expire(w.bounds.now())
+	if w.minDeque.IsEmpty() {
+		err = errWindowEmpty
+		return
+	}
+	return w.minDeque.elems[0].value, nil
+}
+
+// Max returns the largest sample in this SlidingWindow.
+// It returns an error if the window is empty.
+func (w *SlidingWindow[T]) Max() (t T, err error) {
+	w.expire(w.bounds.now())
+	if w.maxDeque.IsEmpty() {
+		err = errWindowEmpty
+		return
+	}
+	return w.maxDeque.elems[0].value, nil
+}
+
+// Basic (mutating) functions
+
+// Add adds a sample to this SlidingWindow, evicting any samples which no
+// longer belong to the window.
+func (w *SlidingWindow[T]) Add(t T) {
+	now := w.bounds.now()
+	sample := windowSample[T]{t, now, w.seq}
+	w.seq++
+
+	w.samples.Enqueue(sample)
+	w.sum += t
+	for !w.minDeque.IsEmpty() && w.minDeque.peekBack().value > t {
+		w.minDeque.dequeueBack()
+	}
+	w.minDeque.Enqueue(sample)
+	for !w.maxDeque.IsEmpty() && w.maxDeque.peekBack().value < t {
+		w.maxDeque.dequeueBack()
+	}
+	w.maxDeque.Enqueue(sample)
+
+	w.expire(now)
+}
+
+// Clear removes all samples from this SlidingWindow.
+func (w *SlidingWindow[T]) Clear() {
+	*w = *newSlidingWindow[T](w.bounds)
+}
+
+// Internal methods
+
+// expire evicts samples which no longer belong to the window.
+func (w *SlidingWindow[T]) expire(now time.Time) {
+	for !w.samples.IsEmpty() && w.bounds.evict(w.samples.Size(), w.samples.elems[0].added, now) {
+		oldest, _ := w.samples.Dequeue()
+		w.sum -= oldest.value
+		if w.minDeque.elems[0].seq == oldest.seq {
+			w.minDeque.Dequeue()
+		}
+		if w.maxDeque.elems[0].seq == oldest.seq {
+			w.maxDeque.Dequeue()
+		}
+	}
+}
+
+// SlidingAggregator maintains an arbitrary associative aggregate of the most
+// recent samples added to it, in amortised O(1) time per sample. Each sample
+// is converted to an aggregate by lift, and aggregates are combined by
+// combine, which must be associative, though not necessarily commutative:
+// the aggregate is always combined in the order samples were added.
+//
+// Like SlidingWindow, the window holds either a fixed number of samples, or
+// the samples added within a fixed duration.
+type SlidingAggregator[T, A any] struct {
+	bounds  windowBounds
+	lift    func(t T) A
+	combine func(a, b A) A
+	// The window is held as two stacks. The top of front is the oldest
+	// sample, and each entry of front stores the aggregate of itself and
+	// every newer sample in front. back holds newer samples, with backAgg
+	// the aggregate of all of them.
+	front   *Stack[aggregatorSample[T, A]]
+	back    *Stack[aggregatorSample[T, A]]
+	backAgg A
+}
+
+type aggregatorSample[T, A any] struct {
+	value T
+	added time.Time
+	agg   A
+}
+
+// Constructors
+
+// NewSlidingAggregator makes a new SlidingAggregator over the last size
+// samples. It panics if size is not positive.
+func NewSlidingAggregator[T, A any](size int, lift func(t T) A, combine func(a, b A) A) *SlidingAggregator[T, A] {
+	return newSlidingAggregator(countBounds(size), lift, combine)
+}
+
+// NewTimeSlidingAggregator makes a new SlidingAggregator over the samples
+// added within the last span, as measured by the given clock. If clock is
+// nil, the system clock is used.
+// It panics if span is not positive.
+func NewTimeSlidingAggregator[T, A any](span time.Duration, clock Clock, lift func(t T) A, combine func(a, b A) A) *SlidingAggregator[T, A] {
+	return newSlidingAggregator(timeBounds(span, clock), lift, combine)
+}
+
+func newSlidingAggregator[T, A any](bounds windowBounds, lift func(t T) A, combine func(a, b A) A) *SlidingAggregator[T, A] {
+	return &SlidingAggregator[T, A]{
+		bounds:  bounds,
+		lift:    lift,
+		combine: combine,
+		front:   NewStack[aggregatorSample[T, A]](0),
+		back:    NewStack[aggregatorSample[T, A]](0),
+	}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of samples currently in this SlidingAggregator.
+func (a *SlidingAggregator[T, A]) Size() int {
+	a.expire(a.bounds.now())
+	return a.front.Size() + a.back.Size()
+}
+
+// IsEmpty returns true if this SlidingAggregator contains no samples.
+func (a *SlidingAggregator[T, A]) IsEmpty() bool {
+	return a.Size() == 0
+}
+
+// Aggregate returns the aggregate of the samples in this SlidingAggregator,
+// combined from oldest to newest.
+// It returns an error if the window is empty.
+func (a *SlidingAggregator[T, A]) Aggregate() (agg A, err error) {
+	a.expire(a.bounds.now())
+	frontAgg, frontErr := a.front.Peek()
+	switch {
+	case frontErr != nil && a.back.IsEmpty():
+		err = errWindowEmpty
+	case frontErr != nil:
+		agg = a.backAgg
+	case a.back.IsEmpty():
+		agg = frontAgg.agg
+	default:
+		agg = a.combine(frontAgg.agg, a.backAgg)
+	}
+	return
+}
+
+// Basic (mutating) functions
+
+// Add adds a sample to this SlidingAggregator, evicting any samples which
+// no longer belong to the window.
+func (a *SlidingAggregator[T, A]) Add(t T) {
+	now := a.bounds.now()
+	lifted := a.lift(t)
+	if a.back.IsEmpty() {
+		a.backAgg = lifted
+	} else {
+		a.backAgg = a.combine(a.backAgg, lifted)
+	}
+	a.back.Push(aggregatorSample[T, A]{t, now, lifted})
+	a.expire(now)
+}
+
+// Clear removes all samples from this SlidingAggregator.
+func (a *SlidingAggregator[T, A]) Clear() {
+	*a = *newSlidingAggregator(a.bounds, a.lift, a.combine)
+}
+
+// Internal methods
+
+// expire evicts samples which no longer belong to the window.
+func (a *SlidingAggregator[T, A]) expire(now time.Time) {
+	for {
+		if a.front.IsEmpty() {
+			a.flip()
+		}
+		oldest, err := a.front.Peek()
+		if err != nil || !a.bounds.evict(a.front.Size()+a.back.Size(), oldest.added, now) {
+			return
+		}
+		a.front.Pop()
+	}
+}
+
+// flip moves every sample from back to front, computing the suffix
+// aggregates stored in front.
+func (a *SlidingAggregator[T, A]) flip() {
+	for !a.back.IsEmpty() {
+		s, _ := a.back.Pop()
+		if newer, err := a.front.Peek(); err == nil {
+			s.agg = a.combine(s.agg, newer.agg)
+		}
+		a.front.Push(s)
+	}
+	var zero A
+	a.backAgg = zero
+}
+
+// Errors
+var errWindowEmpty = fmt.Errorf("window is empty")
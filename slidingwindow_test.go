@@ -0,0 +1,234 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// timedSample is a sample in a brute-force sliding window.
+type timedSample struct {
+	value int
+	added time.Time
+}
+
+// bruteWindow returns the values of the samples in a window of the given
+// size (if span is 0) or span, at time now.
+func bruteWindow(samples []timedSample, size int, span time.Duration, now time.Time) []int {
+	var values []int
+	for _, s := range samples {
+		if span > 0 && s.added.After(now.Add(-span)) {
+			values = append(values, s.value)
+		}
+	}
+	if span == 0 {
+		for _, s := range samples[max(0, len(samples)-size):] {
+			values = append(values, s.value)
+		}
+	}
+	return values
+}
+
+// checkSlidingWindow compares every statistic of w with want.
+func checkSlidingWindow(t *testing.T, w *SlidingWindow[int], want []int) {
+	t.Helper()
+	if w.Size() != len(want) || w.IsEmpty() != (len(want) == 0) {
+		t.Fatalf("Size() = %d, want %d", w.Size(), len(want))
+	}
+	sum := 0
+	for _, v := range want {
+		sum += v
+	}
+	if w.Sum() != sum {
+		t.Fatalf("Sum() = %d, want %d", w.Sum(), sum)
+	}
+	mean, meanErr := w.Mean()
+	lo, minErr := w.Min()
+	hi, maxErr := w.Max()
+	if len(want) == 0 {
+		if meanErr == nil || minErr == nil || maxErr == nil {
+			t.Fatalf("expected errors from an empty window")
+		}
+		return
+	}
+	if meanErr != nil || mean != float64(sum)/float64(len(want)) {
+		t.Fatalf("Mean() = %v, %v; want %v", mean, meanErr, float64(sum)/float64(len(want)))
+	}
+	if minErr != nil || lo != slices.Min(want) {
+		t.Fatalf("Min() = %d, %v; want %d", lo, minErr, slices.Min(want))
+	}
+	if maxErr != nil || hi != slices.Max(want) {
+		t.Fatalf("Max() = %d, %v; want %d", hi, maxErr, slices.Max(want))
+	}
+	// The monotonic deques only hold samples still in the window.
+	if w.minDeque.Size() > len(want) || w.maxDeque.Size() > len(want) {
+		t.Fatalf("deques hold %d and %d samples for a window of %d",
+			w.minDeque.Size(), w.maxDeque.Size(), len(want))
+	}
+}
+
+func TestSlidingWindowAgainstBruteForce(t *testing.T) {
+	r := rand.New(seeded(45))
+	for _, size := range []int{1, 2, 7, 50} {
+		w := NewSlidingWindow[int](size)
+		var samples []timedSample
+		for i := 0; i < 2000; i++ {
+			v := r.IntN(100) - 50
+			w.Add(v)
+			samples = append(samples, timedSample{v, time.Time{}})
+			checkSlidingWindow(t, w, bruteWindow(samples, size, 0, time.Time{}))
+		}
+		w.Clear()
+		checkSlidingWindow(t, w, nil)
+	}
+}
+
+func TestTimeSlidingWindowAgainstBruteForce(t *testing.T) {
+	r := rand.New(seeded(46))
+	const span = 10 * time.Second
+	clock := NewManualClock(clockStart)
+	w := NewTimeSlidingWindow[int](span, clock)
+	var samples []timedSample
+	for i := 0; i < 3000; i++ {
+		if r.IntN(3) > 0 {
+			v := r.IntN(100) - 50
+			w.Add(v)
+			samples = append(samples, timedSample{v, clock.Now()})
+		}
+		// Mostly small steps, with occasional gaps which empty the window.
+		step := time.Duration(r.IntN(2000)) * time.Millisecond
+		if r.IntN(100) == 0 {
+			step = 2 * span
+		}
+		clock.Advance(step)
+		checkSlidingWindow(t, w, bruteWindow(samples, 0, span, clock.Now()))
+	}
+
+	// A sample added exactly span ago has left the window.
+	w.Clear()
+	w.Add(1)
+	clock.Advance(span - time.Nanosecond)
+	if w.Size() != 1 {
+		t.Errorf("sample evicted before span elapsed")
+	}
+	clock.Advance(time.Nanosecond)
+	if w.Size() != 0 {
+		t.Errorf("sample not evicted once span elapsed")
+	}
+}
+
+func TestSlidingWindowMonotonicEviction(t *testing.T) {
+	// For increasing input every sample is the maximum when added, so each
+	// evicts all the others from the max deque; the min deque keeps them all.
+	w := NewSlidingWindow[int](5)
+	for i := 0; i < 20; i++ {
+		w.Add(i)
+		if w.maxDeque.Size() != 1 {
+			t.Fatalf("max deque holds %d samples for increasing input", w.maxDeque.Size())
+		}
+		if w.minDeque.Size() != min(i+1, 5) {
+			t.Fatalf("min deque holds %d samples, want %d", w.minDeque.Size(), min(i+1, 5))
+		}
+	}
+	// Equal values are kept, so the oldest can be evicted by sequence.
+	w.Clear()
+	for i := 0; i < 10; i++ {
+		w.Add(3)
+	}
+	if m, _ := w.Min(); m != 3 || w.minDeque.Size() > 5 {
+		t.Errorf("Min() = %d with %d samples in the deque", m, w.minDeque.Size())
+	}
+
+	if !panics(func() { NewSlidingWindow[int](0) }) {
+		t.Errorf("expected panic for a non-positive size")
+	}
+	if !panics(func() { NewTimeSlidingWindow[int](0, nil) }) {
+		t.Errorf("expected panic for a non-positive span")
+	}
+}
+
+func TestSlidingWindowFloat(t *testing.T) {
+	w := NewSlidingWindow[float64](3)
+	for _, v := range []float64{0.5, -1.5, 2.25, 4} {
+		w.Add(v)
+	}
+	if w.Sum() != 4.75 {
+		t.Errorf("Sum() = %v, want 4.75", w.Sum())
+	}
+	if m, _ := w.Min(); m != -1.5 {
+		t.Errorf("Min() = %v, want -1.5", m)
+	}
+}
+
+// concat is a non-commutative aggregate, which checks that samples are
+// combined in order.
+func concat(a, b string) string { return a + "," + b }
+
+func TestSlidingAggregatorAgainstBruteForce(t *testing.T) {
+	r := rand.New(seeded(47))
+	for _, size := range []int{1, 3, 16} {
+		a := NewSlidingAggregator(size, strconv.Itoa, concat)
+		var samples []timedSample
+		for i := 0; i < 1000; i++ {
+			v := r.IntN(10)
+			a.Add(v)
+			samples = append(samples, timedSample{v, time.Time{}})
+			checkAggregator(t, a, bruteWindow(samples, size, 0, time.Time{}))
+		}
+		a.Clear()
+		checkAggregator(t, a, nil)
+	}
+}
+
+func TestTimeSlidingAggregatorAgainstBruteForce(t *testing.T) {
+	r := rand.New(seeded(48))
+	const span = 5 * time.Second
+	clock := NewManualClock(clockStart)
+	a := NewTimeSlidingAggregator(span, clock, strconv.Itoa, concat)
+	var samples []timedSample
+	for i := 0; i < 2000; i++ {
+		if r.IntN(2) == 0 {
+			v := r.IntN(10)
+			a.Add(v)
+			samples = append(samples, timedSample{v, clock.Now()})
+		}
+		clock.Advance(time.Duration(r.IntN(1500)) * time.Millisecond)
+		checkAggregator(t, a, bruteWindow(samples, 0, span, clock.Now()))
+	}
+}
+
+func checkAggregator(t *testing.T, a *SlidingAggregator[int, string], want []int) {
+	t.Helper()
+	if a.Size() != len(want) || a.IsEmpty() != (len(want) == 0) {
+		t.Fatalf("Size() = %d, want %d", a.Size(), len(want))
+	}
+	agg, err := a.Aggregate()
+	if len(want) == 0 {
+		if err == nil {
+			t.Fatalf("expected error from an empty aggregator, got %q", agg)
+		}
+		return
+	}
+	wantAgg := strconv.Itoa(want[0])
+	for _, v := range want[1:] {
+		wantAgg = concat(wantAgg, strconv.Itoa(v))
+	}
+	if err != nil || agg != wantAgg {
+		t.Fatalf("Aggregate() = %q, %v; want %q", agg, err, wantAgg)
+	}
+}
+
+func TestSlidingAggregatorMax(t *testing.T) {
+	a := NewSlidingAggregator(3, func(t int) int { return t }, func(x, y int) int { return max(x, y) })
+	var got []int
+	for _, v := range []int{5, 1, 2, 3, 0, 0, 0} {
+		a.Add(v)
+		m, _ := a.Aggregate()
+		got = append(got, m)
+	}
+	if want := []int{5, 5, 5, 3, 3, 3, 0}; !slices.Equal(got, want) {
+		t.Errorf("sliding maxima = %v, want %v", got, want)
+	}
+}
@@ -5,18 +5,21 @@ import "fmt"
 // Stack is an implementation of a stack using a slice.
 type Stack[T any] struct {
 	elems []T
+	// shrinkFactor is 0 if this Stack should never release capacity when
+	// elements are popped.
+	shrinkFactor int
 }
 
 // Constructors
 
 // NewStack makes a new Stack with the specified initial capacity.
 func NewStack[T any](capacity int) *Stack[T] {
-	return &Stack[T]{make([]T, 0, capacity)}
+	return &Stack[T]{elems: make([]T, 0, capacity)}
 }
 
 // AsStack returns a Stack backed by the given slice.
 func AsStack[T any](elems []T) *Stack[T] {
-	return &Stack[T]{elems}
+	return &Stack[T]{elems: elems}
 }
 
 // AsSlice returns the underlying slice for this Stack.
@@ -52,6 +55,39 @@ func (s *Stack[T]) Peek() (t T, err error) {
 	return
 }
 
+// PeekN returns the top n elements of this Stack, from the top down,
+// without removing them from the Stack. It returns an error if n is negative
+// or greater than the size of the Stack.
+func (s *Stack[T]) PeekN(n int) (*List[T], error) {
+	if err := checkBatchSize("Stack", n, s.Size()); err != nil {
+		return nil, err
+	}
+	return copyBatch(s.elems[len(s.elems)-n:], true), nil
+}
+
+// ContainsFunc returns true if the given element is in the Stack, using eq
+// to compare elements for equality.
+func (s *Stack[T]) ContainsFunc(t T, eq func(s, t T) bool) bool {
+	_, err := s.SearchFunc(t, eq)
+	return err == nil
+}
+
+// SearchFunc returns the distance from the top of the Stack to the topmost
+// occurrence of the given element, using eq to compare elements for
+// equality. The top element is at distance 0. It returns an error if the
+// element is not found.
+func (s *Stack[T]) SearchFunc(t T, eq func(s, t T) bool) (dist int, err error) {
+	for i := len(s.elems) - 1; i >= 0; i-- {
+		if eq(s.elems[i], t) {
+			dist = len(s.elems) - 1 - i
+			return
+		}
+	}
+
+	err = s.errElementNotFound(t)
+	return
+}
+
 // Basic (mutating) functions
 
 // Push adds the given element to the top of this Stack.
@@ -66,19 +102,152 @@ func (s *Stack[T]) Pop() (t T, err error) {
 		err = errStackEmpty
 	} else {
 		t = s.elems[len(s.elems)-1]
-		s.elems = s.elems[:len(s.elems)-1]
+		s.truncate(len(s.elems) - 1)
 	}
 	return
 }
 
+// PushAll pushes the given elements onto this Stack in order, so the last
+// element ends up on top.
+func (s *Stack[T]) PushAll(ts ...T) {
+	s.elems = append(s.elems, ts...)
+}
+
+// PopN removes the top n elements of this Stack and returns them, from the
+// top down. It returns an error if n is negative or greater than the size of
+// the Stack, in which case the Stack is unchanged.
+func (s *Stack[T]) PopN(n int) (*List[T], error) {
+	popped, err := s.PeekN(n)
+	if err != nil {
+		return nil, err
+	}
+	s.truncate(len(s.elems) - n)
+	return popped, nil
+}
+
+// Dup pushes a copy of the top element onto this Stack.
+// It returns an error if the Stack is empty.
+func (s *Stack[T]) Dup() error {
+	t, err := s.Peek()
+	if err != nil {
+		return err
+	}
+	s.Push(t)
+	return nil
+}
+
+// Swap exchanges the top two elements of this Stack.
+// It returns an error if the Stack has fewer than two elements.
+func (s *Stack[T]) Swap() error {
+	if err := checkBatchSize("Stack", 2, s.Size()); err != nil {
+		return err
+	}
+	n := len(s.elems)
+	s.elems[n-1], s.elems[n-2] = s.elems[n-2], s.elems[n-1]
+	return nil
+}
+
+// Rotate rotates the top n elements of this Stack, moving the nth element
+// from the top to the top, and shifting the elements above it down by one.
+// For example, Rotate(3) turns (bottom to top) a b c into b c a.
+// It returns an error if n is not positive or is greater than the size of
+// the Stack.
+func (s *Stack[T]) Rotate(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("cannot rotate %d elements of Stack", n)
+	}
+	if err := checkBatchSize("Stack", n, s.Size()); err != nil {
+		return err
+	}
+	top := s.elems[len(s.elems)-n:]
+	t := top[0]
+	copy(top, top[1:])
+	top[n-1] = t
+	return nil
+}
+
+// Clear removes all elements from this Stack. The capacity is released if
+// shrinking is enabled (see ShrinkOnPop), and retained otherwise.
+func (s *Stack[T]) Clear() {
+	s.truncate(0)
+}
+
+// ShrinkOnPop configures whether this Stack releases unused capacity as
+// elements are removed. If factor is positive, the capacity is halved
+// whenever the size falls to 1/factor of the capacity or below; a factor of
+// 0 disables shrinking, which is the default.
+// It panics if factor is negative or between 1 and 3, which could cause the
+// Stack to repeatedly grow and shrink.
+func (s *Stack[T]) ShrinkOnPop(factor int) {
+	if factor < 0 || (factor > 0 && factor < 4) {
+		panic(fmt.Sprintf("invalid shrink factor %d: must be 0 or at least 4", factor))
+	}
+	s.shrinkFactor = factor
+}
+
 // Copying functions
 
 // Copy returns a copy of the given Stack.
 func (s *Stack[T]) Copy() *Stack[T] {
-	elemsCp := make([]T, 0, s.Size())
+	elemsCp := make([]T, s.Size())
 	copy(elemsCp, s.elems)
-	return &Stack[T]{elemsCp}
+	return &Stack[T]{elems: elemsCp, shrinkFactor: s.shrinkFactor}
+}
+
+// CopyDeep returns a deep copy of the given Stack, in which each element is
+// copied using clone. If clone is nil, elements implementing Cloner[T] are
+// copied using their Clone method, and other elements are copied as-is.
+func (s *Stack[T]) CopyDeep(clone func(t T) T) *Stack[T] {
+	clone = cloneFunc(clone)
+	elemsCp := make([]T, s.Size())
+	for i, t := range s.elems {
+		elemsCp[i] = clone(t)
+	}
+	return &Stack[T]{elems: elemsCp, shrinkFactor: s.shrinkFactor}
+}
+
+// Functions on Stacks of comparable elements
+
+// StackContains returns true if the given element is in the Stack.
+func StackContains[T comparable](s *Stack[T], t T) bool {
+	return s.ContainsFunc(t, equal[T])
+}
+
+// StackSearch returns the distance from the top of the Stack to the topmost
+// occurrence of the given element, or returns an error if the element is not
+// found.
+func StackSearch[T comparable](s *Stack[T], t T) (int, error) {
+	return s.SearchFunc(t, equal[T])
+}
+
+// Internal methods
+
+// minShrinkCapacity is the smallest capacity a Stack is shrunk to.
+const minShrinkCapacity = 16
+
+// truncate removes all elements above index n, zeroing their slots so they
+// can be garbage collected, then shrinks the Stack if it is configured to.
+func (s *Stack[T]) truncate(n int) {
+	clear(s.elems[n:])
+	s.elems = s.elems[:n]
+	if s.shrinkFactor == 0 {
+		return
+	}
+
+	newCap := cap(s.elems)
+	for newCap > minShrinkCapacity && len(s.elems) <= newCap/s.shrinkFactor {
+		newCap = max(newCap/2, minShrinkCapacity)
+	}
+	if newCap < cap(s.elems) {
+		elems := make([]T, len(s.elems), newCap)
+		copy(elems, s.elems)
+		s.elems = elems
+	}
 }
 
 // Errors
 var errStackEmpty = fmt.Errorf("stack is empty")
+
+func (s *Stack[T]) errElementNotFound(t T) error {
+	return fmt.Errorf("element not found in Stack: %v", t)
+}
@@ -0,0 +1,199 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+// stackOf returns a Stack containing the given elements, bottom to top.
+func stackOf(elems ...string) *Stack[string] {
+	s := NewStack[string](0)
+	s.PushAll(elems...)
+	return s
+}
+
+func TestStackBatchOperations(t *testing.T) {
+	s := stackOf("a", "b", "c", "d")
+
+	peeked, err := s.PeekN(3)
+	if err != nil || !slices.Equal(*peeked, []string{"d", "c", "b"}) || s.Size() != 4 {
+		t.Errorf("PeekN(3) = %v, %v", peeked, err)
+	}
+	popped, err := s.PopN(2)
+	if err != nil || !slices.Equal(*popped, []string{"d", "c"}) {
+		t.Errorf("PopN(2) = %v, %v", popped, err)
+	}
+	if !slices.Equal(s.AsSlice(), []string{"a", "b"}) {
+		t.Errorf("after PopN(2): %v", s.AsSlice())
+	}
+	if empty, err := s.PopN(0); err != nil || empty.Size() != 0 {
+		t.Errorf("PopN(0) = %v, %v", empty, err)
+	}
+	for _, n := range []int{-1, 3} {
+		if _, err := s.PeekN(n); err == nil {
+			t.Errorf("expected error from PeekN(%d)", n)
+		}
+		if _, err := s.PopN(n); err == nil || s.Size() != 2 {
+			t.Errorf("PopN(%d) should fail and leave the Stack unchanged", n)
+		}
+	}
+}
+
+func TestStackManipulation(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   []string
+		op      func(*Stack[string]) error
+		want    []string
+		wantErr bool
+	}{
+		{"Dup", []string{"a", "b"}, (*Stack[string]).Dup, []string{"a", "b", "b"}, false},
+		{"Dup empty", nil, (*Stack[string]).Dup, nil, true},
+		{"Swap", []string{"a", "b", "c"}, (*Stack[string]).Swap, []string{"a", "c", "b"}, false},
+		{"Swap one", []string{"a"}, (*Stack[string]).Swap, []string{"a"}, true},
+		{"Rotate(3)", []string{"a", "b", "c"}, func(s *Stack[string]) error { return s.Rotate(3) },
+			[]string{"b", "c", "a"}, false},
+		{"Rotate(2)", []string{"x", "a", "b"}, func(s *Stack[string]) error { return s.Rotate(2) },
+			[]string{"x", "b", "a"}, false},
+		{"Rotate(1)", []string{"a", "b"}, func(s *Stack[string]) error { return s.Rotate(1) },
+			[]string{"a", "b"}, false},
+		{"Rotate(0)", []string{"a", "b"}, func(s *Stack[string]) error { return s.Rotate(0) },
+			[]string{"a", "b"}, true},
+		{"Rotate too many", []string{"a", "b"}, func(s *Stack[string]) error { return s.Rotate(3) },
+			[]string{"a", "b"}, true},
+	}
+	for _, tt := range tests {
+		s := stackOf(tt.start...)
+		err := tt.op(s)
+		if (err != nil) != tt.wantErr || !slices.Equal(s.AsSlice(), tt.want) {
+			t.Errorf("%s: got %v, %v; want %v (error %v)", tt.name, s.AsSlice(), err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStackSearch(t *testing.T) {
+	s := stackOf("a", "b", "a", "c")
+	tests := []struct {
+		elem  string
+		dist  int
+		found bool
+	}{
+		{"c", 0, true},
+		{"a", 1, true}, // the topmost occurrence
+		{"b", 2, true},
+		{"z", 0, false},
+	}
+	for _, tt := range tests {
+		dist, err := StackSearch(s, tt.elem)
+		if (err == nil) != tt.found || dist != tt.dist {
+			t.Errorf("StackSearch(%q) = %d, %v; want %d", tt.elem, dist, err, tt.dist)
+		}
+		if StackContains(s, tt.elem) != tt.found {
+			t.Errorf("StackContains(%q) = %v", tt.elem, !tt.found)
+		}
+	}
+	eqFold := func(x, y string) bool { return x == y || x == y+y }
+	if dist, err := s.SearchFunc("c", eqFold); err != nil || dist != 0 {
+		t.Errorf("SearchFunc = %d, %v", dist, err)
+	}
+	if !s.ContainsFunc("b", eqFold) {
+		t.Errorf("ContainsFunc(b) = false")
+	}
+}
+
+func TestStackZeroesRemovedSlots(t *testing.T) {
+	s := NewStack[*int](8)
+	for i := 0; i < 8; i++ {
+		s.Push(new(int))
+	}
+	s.Pop()
+	s.PopN(3)
+	tail := s.AsSlice()[s.Size():cap(s.AsSlice())]
+	if slices.ContainsFunc(tail, func(p *int) bool { return p != nil }) {
+		t.Errorf("removed slots still hold pointers: %v", tail)
+	}
+	s.Clear()
+	if s.Capacity() != 8 || slices.ContainsFunc(s.AsSlice()[:8], func(p *int) bool { return p != nil }) {
+		t.Errorf("Clear should zero the slots and keep the capacity without shrinking")
+	}
+}
+
+func TestStackShrinkPolicy(t *testing.T) {
+	for _, factor := range []int{4, 8} {
+		s := NewStack[int](0)
+		s.ShrinkOnPop(factor)
+		for i := 0; i < 1000; i++ {
+			s.Push(i)
+		}
+
+		shrinks := 0
+		for !s.IsEmpty() {
+			before := s.Capacity()
+			if s.Size()%3 == 0 && s.Size() >= 3 {
+				s.PopN(3)
+			} else {
+				s.Pop()
+			}
+			if s.Capacity() < before {
+				shrinks++
+			}
+			// capacity is only released down to minShrinkCapacity, and
+			// never while the Stack occupies more than 1/factor of it
+			if c := s.Capacity(); c > minShrinkCapacity && s.Size() <= c/factor {
+				t.Fatalf("factor %d: size %d with capacity %d was not shrunk", factor, s.Size(), c)
+			}
+			if c := s.Capacity(); c < minShrinkCapacity {
+				t.Fatalf("factor %d: capacity %d below the minimum", factor, c)
+			}
+		}
+		if shrinks == 0 || s.Capacity() != minShrinkCapacity {
+			t.Errorf("factor %d: %d shrinks, final capacity %d", factor, shrinks, s.Capacity())
+		}
+
+		// After shrinking there is room to grow, so alternating pushes
+		// and pops near the threshold do not reallocate every time.
+		for i := 0; i < 200; i++ {
+			s.Push(i)
+		}
+		s.PopN(200 - 200/factor)
+		c := s.Capacity()
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+			s.Pop()
+		}
+		if s.Capacity() != c {
+			t.Errorf("factor %d: capacity changed from %d to %d by alternating Push and Pop", factor, c, s.Capacity())
+		}
+	}
+
+	s := NewStack[int](0)
+	for i := 0; i < 1000; i++ {
+		s.Push(i)
+	}
+	c := s.Capacity()
+	s.PopN(999)
+	s.Clear()
+	if s.Capacity() != c {
+		t.Errorf("Stack shrank from %d to %d without ShrinkOnPop", c, s.Capacity())
+	}
+
+	for _, factor := range []int{-1, 1, 3} {
+		if !panics(func() { s.ShrinkOnPop(factor) }) {
+			t.Errorf("expected panic from ShrinkOnPop(%d)", factor)
+		}
+	}
+}
+
+func TestStackCopy(t *testing.T) {
+	s := stackOf("a", "b", "c")
+	s.ShrinkOnPop(4)
+	cp := s.Copy()
+	if !slices.Equal(cp.AsSlice(), s.AsSlice()) || cp.shrinkFactor != 4 {
+		t.Fatalf("Copy() = %v", cp.AsSlice())
+	}
+	cp.Push("d")
+	cp.Swap()
+	if !slices.Equal(s.AsSlice(), []string{"a", "b", "c"}) {
+		t.Errorf("modifying a copy changed the original: %v", s.AsSlice())
+	}
+}
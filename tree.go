@@ -0,0 +1,296 @@
+package collections
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tree is a node in a rooted tree, where each node holds a value and has any
+// number of ordered children. A Tree value represents both a single node and
+// the subtree rooted at that node.
+type Tree[T any] struct {
+	Value    T
+	parent   *Tree[T]
+	children []*Tree[T]
+}
+
+// String returns an ASCII-art representation of the subtree rooted at this
+// node, with one node per line, e.g.
+//
+//	root
+//	├── a
+//	│   └── c
+//	└── b
+func (t *Tree[T]) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v\n", t.Value)
+	t.writeChildren(&sb, "")
+	return sb.String()
+}
+
+func (t *Tree[T]) writeChildren(sb *strings.Builder, indent string) {
+	for i, c := range t.children {
+		branch, childIndent := "├── ", "│   "
+		if i == len(t.children)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+		fmt.Fprintf(sb, "%s%s%v\n", indent, branch, c.Value)
+		c.writeChildren(sb, indent+childIndent)
+	}
+}
+
+// Constructors
+
+// NewTree makes a new Tree consisting of a single root node with the given
+// value.
+func NewTree[T any](value T) *Tree[T] {
+	return &Tree[T]{Value: value}
+}
+
+// Basic (non-mutating) functions
+
+// Parent returns the parent of this node, or nil if it is a root.
+func (t *Tree[T]) Parent() *Tree[T] {
+	return t.parent
+}
+
+// Children returns the children of this node, in order.
+func (t *Tree[T]) Children() *List[*Tree[T]] {
+	children := NewList[*Tree[T]](len(t.children))
+	children.Append(t.children...)
+	return children
+}
+
+// Child returns the child of this node at index i. Like List.Get, it accepts
+// negative indices, and returns an error if the index is out of bounds.
+func (t *Tree[T]) Child(i int) (*Tree[T], error) {
+	return AsList(t.children).Get(i)
+}
+
+// IsRoot returns true if this node has no parent.
+func (t *Tree[T]) IsRoot() bool {
+	return t.parent == nil
+}
+
+// IsLeaf returns true if this node has no children.
+func (t *Tree[T]) IsLeaf() bool {
+	return len(t.children) == 0
+}
+
+// Root returns the root of the tree containing this node.
+func (t *Tree[T]) Root() *Tree[T] {
+	for t.parent != nil {
+		t = t.parent
+	}
+	return t
+}
+
+// Size returns the number of nodes in the subtree rooted at this node.
+func (t *Tree[T]) Size() int {
+	size := 0
+	it := t.PreOrder()
+	for it.HasNext() {
+		it.Next()
+		size++
+	}
+	return size
+}
+
+// Depth returns the number of edges between this node and the root.
+func (t *Tree[T]) Depth() int {
+	depth := 0
+	for n := t.parent; n != nil; n = n.parent {
+		depth++
+	}
+	return depth
+}
+
+// Height returns the number of edges on the longest path from this node down
+// to a leaf.
+func (t *Tree[T]) Height() int {
+	height := 0
+	for _, c := range t.children {
+		height = max(height, c.Height()+1)
+	}
+	return height
+}
+
+// PathToRoot returns the nodes on the path from this node up to the root,
+// inclusive.
+func (t *Tree[T]) PathToRoot() *List[*Tree[T]] {
+	path := NewList[*Tree[T]](t.Depth() + 1)
+	for n := t; n != nil; n = n.parent {
+		path.Append(n)
+	}
+	return path
+}
+
+// LowestCommonAncestor returns the deepest node which is an ancestor of both
+// a and b, where each node counts as an ancestor of itself. It returns an
+// error if a and b are in different trees.
+func LowestCommonAncestor[T any](a, b *Tree[T]) (*Tree[T], error) {
+	da, db := a.Depth(), b.Depth()
+	for ; da > db; da-- {
+		a = a.parent
+	}
+	for ; db > da; db-- {
+		b = b.parent
+	}
+	for a != b {
+		a, b = a.parent, b.parent
+	}
+	if a == nil {
+		return nil, errDifferentTrees
+	}
+	return a, nil
+}
+
+// Basic (mutating) functions
+
+// AddChild adds a new node with the given value as the last child of this
+// node, and returns the new node.
+func (t *Tree[T]) AddChild(value T) *Tree[T] {
+	child := NewTree(value)
+	child.parent = t
+	t.children = append(t.children, child)
+	return child
+}
+
+// AttachChild adds the tree rooted at child as the last child of this node.
+// It returns an error if child is not a root, or if it is the root of this
+// node's tree (which would create a cycle).
+func (t *Tree[T]) AttachChild(child *Tree[T]) error {
+	if !child.IsRoot() {
+		return fmt.Errorf("cannot attach non-root node %v", child.Value)
+	}
+	if t.Root() == child {
+		return fmt.Errorf("cannot attach node %v to its own descendant", child.Value)
+	}
+	child.parent = t
+	t.children = append(t.children, child)
+	return nil
+}
+
+// Detach removes this node (and its subtree) from its parent, making it the
+// root of a separate tree. It does nothing if this node is already a root.
+func (t *Tree[T]) Detach() {
+	if t.parent == nil {
+		return
+	}
+	siblings := t.parent.children
+	for i, s := range siblings {
+		if s == t {
+			copy(siblings[i:], siblings[i+1:])
+			siblings[len(siblings)-1] = nil
+			t.parent.children = siblings[:len(siblings)-1]
+			break
+		}
+	}
+	t.parent = nil
+}
+
+// Copying functions
+
+// Copy returns a copy of the subtree rooted at this node. The copy is a
+// separate tree, whose root has no parent. Values are copied by assignment.
+func (t *Tree[T]) Copy() *Tree[T] {
+	cp := NewTree(t.Value)
+	for _, c := range t.children {
+		cc := c.Copy()
+		cc.parent = cp
+		cp.children = append(cp.children, cc)
+	}
+	return cp
+}
+
+// Iteration
+
+type preOrderIterator[T any] struct {
+	stack *Stack[*Tree[T]]
+}
+
+func (i *preOrderIterator[T]) HasNext() bool {
+	return !i.stack.IsEmpty()
+}
+
+func (i *preOrderIterator[T]) Next() *Tree[T] {
+	n, _ := i.stack.Pop()
+	// push in reverse so the first child is visited first
+	for j := len(n.children) - 1; j >= 0; j-- {
+		i.stack.Push(n.children[j])
+	}
+	return n
+}
+
+// PreOrder returns an Iterator over the subtree rooted at this node in
+// pre-order: each node is visited before its children. The tree must not be
+// modified during iteration.
+func (t *Tree[T]) PreOrder() Iterator[*Tree[T]] {
+	it := &preOrderIterator[T]{NewStack[*Tree[T]](0)}
+	it.stack.Push(t)
+	return it
+}
+
+type postOrderFrame[T any] struct {
+	node *Tree[T]
+	// next is the index of the next child to visit.
+	next int
+}
+
+type postOrderIterator[T any] struct {
+	stack *Stack[*postOrderFrame[T]]
+}
+
+func (i *postOrderIterator[T]) HasNext() bool {
+	return !i.stack.IsEmpty()
+}
+
+func (i *postOrderIterator[T]) Next() *Tree[T] {
+	for {
+		f, _ := i.stack.Peek()
+		if f.next == len(f.node.children) {
+			i.stack.Pop()
+			return f.node
+		}
+		i.stack.Push(&postOrderFrame[T]{f.node.children[f.next], 0})
+		f.next++
+	}
+}
+
+// PostOrder returns an Iterator over the subtree rooted at this node in
+// post-order: each node is visited after its children. The tree must not be
+// modified during iteration.
+func (t *Tree[T]) PostOrder() Iterator[*Tree[T]] {
+	it := &postOrderIterator[T]{NewStack[*postOrderFrame[T]](0)}
+	it.stack.Push(&postOrderFrame[T]{t, 0})
+	return it
+}
+
+type levelOrderIterator[T any] struct {
+	queue *Queue[*Tree[T]]
+}
+
+func (i *levelOrderIterator[T]) HasNext() bool {
+	return !i.queue.IsEmpty()
+}
+
+func (i *levelOrderIterator[T]) Next() *Tree[T] {
+	n, _ := i.queue.Dequeue()
+	for _, c := range n.children {
+		i.queue.Enqueue(c)
+	}
+	return n
+}
+
+// LevelOrder returns an Iterator over the subtree rooted at this node in
+// level order (breadth-first): all nodes at each depth are visited, from
+// left to right, before any deeper node. The tree must not be modified
+// during iteration.
+func (t *Tree[T]) LevelOrder() Iterator[*Tree[T]] {
+	it := &levelOrderIterator[T]{NewQueue[*Tree[T]](0)}
+	it.queue.Enqueue(t)
+	return it
+}
+
+// Errors
+var errDifferentTrees = fmt.Errorf("nodes are in different trees")
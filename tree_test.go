@@ -0,0 +1,216 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// randomTree returns a random tree with n nodes valued 0..n-1, where each
+// node is added as a child of a random earlier node, and the nodes indexed
+// by value.
+func randomTree(r *rand.Rand, n int) (*Tree[int], []*Tree[int]) {
+	nodes := []*Tree[int]{NewTree(0)}
+	for i := 1; i < n; i++ {
+		nodes = append(nodes, nodes[r.IntN(i)].AddChild(i))
+	}
+	return nodes[0], nodes
+}
+
+func treeValues(it Iterator[*Tree[int]]) []int {
+	var values []int
+	for it.HasNext() {
+		values = append(values, it.Next().Value)
+	}
+	return values
+}
+
+// recursive reference traversals
+func preOrder(t *Tree[int], values []int) []int {
+	values = append(values, t.Value)
+	for _, c := range t.children {
+		values = preOrder(c, values)
+	}
+	return values
+}
+
+func postOrder(t *Tree[int], values []int) []int {
+	for _, c := range t.children {
+		values = postOrder(c, values)
+	}
+	return append(values, t.Value)
+}
+
+func TestTreeTraversalOrders(t *testing.T) {
+	//	0
+	//	├── 1
+	//	│   ├── 3
+	//	│   └── 4
+	//	│       └── 6
+	//	└── 2
+	//	    └── 5
+	root := NewTree(0)
+	n1, n2 := root.AddChild(1), root.AddChild(2)
+	n1.AddChild(3)
+	n1.AddChild(4).AddChild(6)
+	n2.AddChild(5)
+
+	tests := []struct {
+		name string
+		it   Iterator[*Tree[int]]
+		want []int
+	}{
+		{"PreOrder", root.PreOrder(), []int{0, 1, 3, 4, 6, 2, 5}},
+		{"PostOrder", root.PostOrder(), []int{3, 6, 4, 1, 5, 2, 0}},
+		{"LevelOrder", root.LevelOrder(), []int{0, 1, 2, 3, 4, 5, 6}},
+		{"subtree PreOrder", n1.PreOrder(), []int{1, 3, 4, 6}},
+		{"leaf PostOrder", (*n2.Children())[0].PostOrder(), []int{5}},
+	}
+	for _, tt := range tests {
+		if got := treeValues(tt.it); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	want := "0\n" +
+		"├── 1\n" +
+		"│   ├── 3\n" +
+		"│   └── 4\n" +
+		"│       └── 6\n" +
+		"└── 2\n" +
+		"    └── 5\n"
+	if got := root.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if got := n2.String(); got != "2\n└── 5\n" {
+		t.Errorf("subtree String() = %q", got)
+	}
+}
+
+func TestTreeRandomTraversals(t *testing.T) {
+	r := rand.New(seeded(42))
+	for trial := 0; trial < 50; trial++ {
+		root, nodes := randomTree(r, 1+r.IntN(60))
+		if got, want := treeValues(root.PreOrder()), preOrder(root, nil); !slices.Equal(got, want) {
+			t.Fatalf("PreOrder() = %v, want %v", got, want)
+		}
+		if got, want := treeValues(root.PostOrder()), postOrder(root, nil); !slices.Equal(got, want) {
+			t.Fatalf("PostOrder() = %v, want %v", got, want)
+		}
+		level := treeValues(root.LevelOrder())
+		for i := 1; i < len(level); i++ {
+			if nodes[level[i-1]].Depth() > nodes[level[i]].Depth() {
+				t.Fatalf("LevelOrder() = %v is not in order of depth", level)
+			}
+		}
+		if len(level) != len(nodes) || root.Size() != len(nodes) {
+			t.Fatalf("LevelOrder() visited %d nodes, Size() = %d; want %d",
+				len(level), root.Size(), len(nodes))
+		}
+
+		height := 0
+		for _, n := range nodes {
+			height = max(height, n.Depth())
+			if n.Root() != root || n.PathToRoot().Size() != n.Depth()+1 {
+				t.Fatalf("node %d: wrong Root or PathToRoot", n.Value)
+			}
+		}
+		if root.Height() != height {
+			t.Fatalf("Height() = %d, want %d", root.Height(), height)
+		}
+	}
+}
+
+func TestTreeLowestCommonAncestor(t *testing.T) {
+	r := rand.New(seeded(43))
+	for trial := 0; trial < 50; trial++ {
+		_, nodes := randomTree(r, 2+r.IntN(40))
+		for i := 0; i < 50; i++ {
+			a, b := nodes[r.IntN(len(nodes))], nodes[r.IntN(len(nodes))]
+			// brute force: the first ancestor of a which is an ancestor of b
+			ancestorsOfB := NewSet[*Tree[int]](0)
+			ancestorsOfB.AddAll(*b.PathToRoot()...)
+			var want *Tree[int]
+			for _, n := range *a.PathToRoot() {
+				if ancestorsOfB.Contains(n) {
+					want = n
+					break
+				}
+			}
+			if got, err := LowestCommonAncestor(a, b); err != nil || got != want {
+				t.Fatalf("LowestCommonAncestor(%d, %d) = %v, %v; want %d", a.Value, b.Value, got, err, want.Value)
+			}
+		}
+	}
+
+	if _, err := LowestCommonAncestor(NewTree(1), NewTree(1)); err == nil {
+		t.Errorf("expected error for nodes in different trees")
+	}
+}
+
+func TestTreeDetachAndAttach(t *testing.T) {
+	root := NewTree("root")
+	a := root.AddChild("a")
+	b := root.AddChild("b")
+	c := root.AddChild("c")
+	a.AddChild("a1")
+
+	a.Detach()
+	if !a.IsRoot() || a.Root() != a || root.Children().Size() != 2 {
+		t.Fatalf("Detach did not separate the subtree")
+	}
+	if got := *root.Children(); !slices.Equal(got, []*Tree[string]{b, c}) {
+		t.Errorf("siblings after Detach = %v", got)
+	}
+	if _, err := LowestCommonAncestor(a, b); err == nil {
+		t.Errorf("detached node still shares an ancestor with its old sibling")
+	}
+	a.Detach() // no-op on a root
+
+	if err := c.AttachChild(a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Parent() != c || a.Depth() != 2 || root.Size() != 5 {
+		t.Errorf("AttachChild did not attach the subtree")
+	}
+	if err := b.AttachChild(a); err == nil {
+		t.Errorf("expected error attaching a non-root node")
+	}
+	if err := (*a.Children())[0].AttachChild(root); err == nil {
+		t.Errorf("expected error attaching a root to its own descendant")
+	}
+
+	if child, err := root.Child(-1); err != nil || child != c {
+		t.Errorf("Child(-1) = %v, %v", child, err)
+	}
+	if _, err := root.Child(2); err == nil {
+		t.Errorf("expected error from Child out of bounds")
+	}
+}
+
+func TestTreeCopy(t *testing.T) {
+	root := NewTree(0)
+	n1 := root.AddChild(1)
+	n1.AddChild(2)
+	root.AddChild(3)
+
+	cp := n1.Copy()
+	if !cp.IsRoot() || cp.String() != n1.String() {
+		t.Fatalf("Copy() = %q, want a root with the same structure as %q", cp.String(), n1.String())
+	}
+	for it := cp.PreOrder(); it.HasNext(); {
+		n := it.Next()
+		for _, c := range n.children {
+			if c.parent != n {
+				t.Fatalf("copied node %d has the wrong parent", c.Value)
+			}
+		}
+	}
+
+	cp.Value = 10
+	cp.AddChild(4)
+	(*cp.Children())[0].Value = 20
+	if n1.Value != 1 || n1.Size() != 2 || (*n1.Children())[0].Value != 2 {
+		t.Errorf("modifying a copy changed the original: %q", n1.String())
+	}
+}
@@ -0,0 +1,354 @@
+package collections
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Trie is an implementation of a map from strings to values using a prefix
+// tree, which supports efficient lookups by prefix. Keys are treated as
+// sequences of bytes, and iteration is in lexicographic (byte) order.
+//
+// Each node of a Trie represents a single byte. RadixTree provides the same
+// API using less memory, by compressing chains of nodes with a single child.
+type Trie[V any] struct {
+	root trieNode[V]
+}
+
+// trieNode is a node in a Trie or RadixTree.
+type trieNode[V any] struct {
+	// label is the label of the edge leading to this node. In a Trie, labels
+	// are always a single byte.
+	label string
+	// children are sorted by the first byte of their labels, which is unique.
+	children []*trieNode[V]
+	value    V
+	hasValue bool
+	// count is the number of keys in the subtree rooted at this node.
+	count int
+}
+
+// Constructors
+
+// NewTrie makes a new empty Trie.
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// Basic (non-mutating) functions
+
+// Size returns the number of keys in this Trie.
+func (t *Trie[V]) Size() int {
+	return t.root.count
+}
+
+// IsEmpty returns true if this Trie is empty.
+func (t *Trie[V]) IsEmpty() bool {
+	return t.Size() == 0
+}
+
+// Contains returns true if the given key is in the Trie.
+func (t *Trie[V]) Contains(key string) bool {
+	n := t.root.find(key)
+	return n != nil && n.hasValue
+}
+
+// Get returns the value associated with key. If key is not in this Trie, Get
+// returns an error.
+func (t *Trie[V]) Get(key string) (V, error) {
+	return t.root.get(key)
+}
+
+// LongestPrefixMatch returns the longest key in this Trie which is a prefix
+// of s, along with its value. It returns an error if no key is a prefix of s.
+func (t *Trie[V]) LongestPrefixMatch(s string) (string, V, error) {
+	return t.root.longestPrefixMatch(s)
+}
+
+// CountPrefix returns the number of keys in this Trie which start with the
+// given prefix.
+func (t *Trie[V]) CountPrefix(prefix string) int {
+	n, _ := t.root.findPrefix(prefix)
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
+
+// KeysWithPrefix returns an Iterator over the keys in this Trie which start
+// with the given prefix, in lexicographic order. The keys are found lazily,
+// so the Trie must not be modified during iteration.
+func (t *Trie[V]) KeysWithPrefix(prefix string) Iterator[string] {
+	return &trieKeyIterator[V]{newTrieIterator(&t.root, prefix)}
+}
+
+// IterateWithPrefix returns an Iterator2 over the keys in this Trie which
+// start with the given prefix and their values, in lexicographic order of the
+// keys. The entries are found lazily, so the Trie must not be modified during
+// iteration.
+func (t *Trie[V]) IterateWithPrefix(prefix string) Iterator2[string, V] {
+	return newTrieIterator(&t.root, prefix)
+}
+
+// Basic (mutating) functions
+
+// Insert associates v with key in this Trie. If there is already a value
+// associated with key, it will be overwritten.
+func (t *Trie[V]) Insert(key string, v V) {
+	if n := t.root.find(key); n != nil && n.hasValue {
+		n.value = v
+		return
+	}
+
+	n := &t.root
+	n.count++
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			n.insertChild(j, &trieNode[V]{label: key[i : i+1]})
+		}
+		n = n.children[j]
+		n.count++
+	}
+	n.value, n.hasValue = v, true
+}
+
+// Delete removes key and its associated value from this Trie. It returns
+// false if key was not in the Trie to begin with, and returns true if key was
+// removed.
+func (t *Trie[V]) Delete(key string) bool {
+	if !t.Contains(key) {
+		return false
+	}
+
+	n := &t.root
+	n.count--
+	for i := 0; i < len(key); i++ {
+		j, _ := n.child(key[i])
+		c := n.children[j]
+		c.count--
+		if c.count == 0 {
+			// no keys left below here - prune the subtree
+			n.removeChild(j)
+			return true
+		}
+		n = c
+	}
+	var z V
+	n.value, n.hasValue = z, false
+	return true
+}
+
+// Clear removes all keys from this Trie.
+func (t *Trie[V]) Clear() {
+	t.root = trieNode[V]{}
+}
+
+// Helpers for String
+
+// InsertString associates v with key in this Trie.
+func (t *Trie[V]) InsertString(key String, v V) {
+	t.Insert(string(key), v)
+}
+
+// GetString returns the value associated with key, or an error if key is not
+// in this Trie.
+func (t *Trie[V]) GetString(key String) (V, error) {
+	return t.Get(string(key))
+}
+
+// DeleteString removes key and its associated value from this Trie, and
+// returns true if key was removed.
+func (t *Trie[V]) DeleteString(key String) bool {
+	return t.Delete(string(key))
+}
+
+// LongestPrefixMatchString returns the longest key in this Trie which is a
+// prefix of s, along with its value, or an error if there is no such key.
+func (t *Trie[V]) LongestPrefixMatchString(s String) (String, V, error) {
+	key, v, err := t.LongestPrefixMatch(string(s))
+	return String(key), v, err
+}
+
+// KeysWithPrefixString returns an Iterator over the keys in this Trie which
+// start with the given prefix, in lexicographic order.
+func (t *Trie[V]) KeysWithPrefixString(prefix String) Iterator[String] {
+	return &trieStringIterator{t.KeysWithPrefix(string(prefix))}
+}
+
+// Iteration
+
+type trieFrame[V any] struct {
+	node *trieNode[V]
+	key  string
+}
+
+// trieIterator performs a pre-order traversal of a subtree, which visits the
+// keys in lexicographic order.
+type trieIterator[V any] struct {
+	stack *Stack[trieFrame[V]]
+	// next entry to return, if ok
+	key   string
+	value V
+	ok    bool
+}
+
+func newTrieIterator[V any](root *trieNode[V], prefix string) *trieIterator[V] {
+	it := &trieIterator[V]{stack: NewStack[trieFrame[V]](0)}
+	if n, key := root.findPrefix(prefix); n != nil {
+		it.stack.Push(trieFrame[V]{n, key})
+	}
+	it.advance()
+	return it
+}
+
+func (i *trieIterator[V]) advance() {
+	for !i.stack.IsEmpty() {
+		f, _ := i.stack.Pop()
+		// push in reverse so the smallest child is visited first
+		for j := len(f.node.children) - 1; j >= 0; j-- {
+			c := f.node.children[j]
+			i.stack.Push(trieFrame[V]{c, f.key + c.label})
+		}
+		if f.node.hasValue {
+			i.key, i.value, i.ok = f.key, f.node.value, true
+			return
+		}
+	}
+	i.ok = false
+}
+
+func (i *trieIterator[V]) HasNext() bool {
+	return i.ok
+}
+
+func (i *trieIterator[V]) Next() (string, V) {
+	key, value := i.key, i.value
+	i.advance()
+	return key, value
+}
+
+type trieKeyIterator[V any] struct {
+	it *trieIterator[V]
+}
+
+func (i *trieKeyIterator[V]) HasNext() bool {
+	return i.it.HasNext()
+}
+
+func (i *trieKeyIterator[V]) Next() string {
+	key, _ := i.it.Next()
+	return key
+}
+
+type trieStringIterator struct {
+	it Iterator[string]
+}
+
+func (i *trieStringIterator) HasNext() bool {
+	return i.it.HasNext()
+}
+
+func (i *trieStringIterator) Next() String {
+	return String(i.it.Next())
+}
+
+// Node methods, shared by Trie and RadixTree
+
+// child returns the index of the child whose label starts with b, and
+// whether it exists. If not, the index is where it would be inserted.
+func (n *trieNode[V]) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label[0] >= b
+	})
+	return i, i < len(n.children) && n.children[i].label[0] == b
+}
+
+func (n *trieNode[V]) insertChild(i int, c *trieNode[V]) {
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+func (n *trieNode[V]) removeChild(i int) {
+	n.children = append(n.children[:i], n.children[i+1:]...)
+}
+
+// find returns the node at the end of the path key, or nil if there is none.
+func (n *trieNode[V]) find(key string) *trieNode[V] {
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok || !strings.HasPrefix(key, n.children[i].label) {
+			return nil
+		}
+		n = n.children[i]
+		key = key[len(n.label):]
+	}
+	return n
+}
+
+// findPrefix returns the root of the smallest subtree containing all keys
+// with the given prefix, and the key of that root. It returns nil if there
+// are no such keys.
+func (n *trieNode[V]) findPrefix(prefix string) (*trieNode[V], string) {
+	var path strings.Builder
+	for prefix != "" {
+		i, ok := n.child(prefix[0])
+		if !ok {
+			return nil, ""
+		}
+		c := n.children[i]
+		switch {
+		case strings.HasPrefix(prefix, c.label):
+			prefix = prefix[len(c.label):]
+		case strings.HasPrefix(c.label, prefix):
+			// prefix ends part way along the edge to c
+			prefix = ""
+		default:
+			return nil, ""
+		}
+		path.WriteString(c.label)
+		n = c
+	}
+	if n.count == 0 {
+		return nil, ""
+	}
+	return n, path.String()
+}
+
+func (n *trieNode[V]) get(key string) (v V, err error) {
+	node := n.find(key)
+	if node == nil || !node.hasValue {
+		err = errTrieKeyNotFound(key)
+		return
+	}
+	return node.value, nil
+}
+
+func (n *trieNode[V]) longestPrefixMatch(s string) (key string, v V, err error) {
+	found := n.hasValue
+	v = n.value
+	depth := 0
+	for depth < len(s) {
+		i, ok := n.child(s[depth])
+		if !ok || !strings.HasPrefix(s[depth:], n.children[i].label) {
+			break
+		}
+		n = n.children[i]
+		depth += len(n.label)
+		if n.hasValue {
+			found, key, v = true, s[:depth], n.value
+		}
+	}
+	if !found {
+		err = fmt.Errorf("no key is a prefix of %q", s)
+	}
+	return
+}
+
+// Errors
+
+func errTrieKeyNotFound(key string) error {
+	return fmt.Errorf("key not found in prefix tree: %q", key)
+}
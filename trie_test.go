@@ -0,0 +1,207 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// prefixMap is the API shared by Trie[int] and RadixTree[int].
+type prefixMap interface {
+	Size() int
+	Contains(key string) bool
+	Get(key string) (int, error)
+	LongestPrefixMatch(s string) (string, int, error)
+	CountPrefix(prefix string) int
+	KeysWithPrefix(prefix string) Iterator[string]
+	IterateWithPrefix(prefix string) Iterator2[string, int]
+	Insert(key string, v int)
+	Delete(key string) bool
+	Clear()
+}
+
+// randomKey returns a short key over a small alphabet, so that keys often
+// share prefixes and are prefixes of each other.
+func randomKey(r *rand.Rand) string {
+	b := make([]byte, r.IntN(6))
+	for i := range b {
+		b[i] = "abc"[r.IntN(3)]
+	}
+	return string(b)
+}
+
+// checkPrefixQueries compares every query on m with a brute-force search of
+// ref, for the given prefix.
+func checkPrefixQueries(t *testing.T, m prefixMap, ref map[string]int, prefix string) {
+	t.Helper()
+	var want []string
+	for k := range ref {
+		if strings.HasPrefix(k, prefix) {
+			want = append(want, k)
+		}
+	}
+	slices.Sort(want)
+
+	if got := m.CountPrefix(prefix); got != len(want) {
+		t.Fatalf("CountPrefix(%q) = %d, want %d", prefix, got, len(want))
+	}
+	var got []string
+	for it := m.KeysWithPrefix(prefix); it.HasNext(); {
+		got = append(got, it.Next())
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("KeysWithPrefix(%q) = %q, want %q", prefix, got, want)
+	}
+	i := 0
+	for it := m.IterateWithPrefix(prefix); it.HasNext(); i++ {
+		k, v := it.Next()
+		if i >= len(want) || k != want[i] || v != ref[k] {
+			t.Fatalf("IterateWithPrefix(%q) gave (%q, %d) at position %d", prefix, k, v, i)
+		}
+	}
+	if i != len(want) {
+		t.Fatalf("IterateWithPrefix(%q) gave %d entries, want %d", prefix, i, len(want))
+	}
+
+	// The longest key that is a prefix of the query string.
+	wantKey, wantOK := "", false
+	for k := range ref {
+		if strings.HasPrefix(prefix, k) && (!wantOK || len(k) > len(wantKey)) {
+			wantKey, wantOK = k, true
+		}
+	}
+	key, v, err := m.LongestPrefixMatch(prefix)
+	switch {
+	case !wantOK && err == nil:
+		t.Fatalf("LongestPrefixMatch(%q) = %q, want error", prefix, key)
+	case wantOK && (err != nil || key != wantKey || v != ref[wantKey]):
+		t.Fatalf("LongestPrefixMatch(%q) = %q, %d, %v; want %q, %d",
+			prefix, key, v, err, wantKey, ref[wantKey])
+	}
+}
+
+// testPrefixMapAgainstMap applies random operations to m and a map, checking
+// that they agree and that check(m) passes after each operation.
+func testPrefixMapAgainstMap(t *testing.T, m prefixMap, check func()) {
+	r := rand.New(seeded(37))
+	ref := map[string]int{}
+	for i := 0; i < 5000; i++ {
+		key := randomKey(r)
+		switch r.IntN(3) {
+		case 0, 1:
+			m.Insert(key, i)
+			ref[key] = i
+		case 2:
+			_, want := ref[key]
+			if got := m.Delete(key); got != want {
+				t.Fatalf("Delete(%q) = %v, want %v", key, got, want)
+			}
+			delete(ref, key)
+		}
+		if i%1000 == 999 {
+			m.Clear()
+			clear(ref)
+		}
+		check()
+
+		if m.Size() != len(ref) {
+			t.Fatalf("Size() = %d, want %d", m.Size(), len(ref))
+		}
+		query := randomKey(r)
+		want, ok := ref[query]
+		if m.Contains(query) != ok {
+			t.Fatalf("Contains(%q) = %v, want %v", query, !ok, ok)
+		}
+		if got, err := m.Get(query); (err == nil) != ok || got != want {
+			t.Fatalf("Get(%q) = %d, %v; want %d, %v", query, got, err, want, ok)
+		}
+		checkPrefixQueries(t, m, ref, query)
+	}
+}
+
+// checkTrieNode checks the invariants of the subtree rooted at n, returning
+// the number of keys in it. If radix is false, every label must be a single
+// byte; if true, every node without a value must have several children.
+func checkTrieNode[V any](t *testing.T, n *trieNode[V], isRoot, radix bool) int {
+	t.Helper()
+	count := 0
+	if n.hasValue {
+		count++
+	}
+	for i, c := range n.children {
+		if c.label == "" {
+			t.Fatalf("empty label below %q", n.label)
+		}
+		if !radix && len(c.label) != 1 {
+			t.Fatalf("Trie label %q is not a single byte", c.label)
+		}
+		if i > 0 && n.children[i-1].label[0] >= c.label[0] {
+			t.Fatalf("children of %q not sorted by first byte", n.label)
+		}
+		count += checkTrieNode(t, c, false, radix)
+	}
+	if count != n.count {
+		t.Fatalf("node %q has count %d, want %d", n.label, n.count, count)
+	}
+	if !isRoot && count == 0 {
+		t.Fatalf("node %q has no keys below it", n.label)
+	}
+	if radix && !isRoot && !n.hasValue && len(n.children) < 2 {
+		t.Fatalf("RadixTree node %q has no value and %d children", n.label, len(n.children))
+	}
+	return count
+}
+
+func TestTrieAgainstMap(t *testing.T) {
+	tr := NewTrie[int]()
+	testPrefixMapAgainstMap(t, tr, func() {
+		checkTrieNode(t, &tr.root, true, false)
+	})
+}
+
+func TestTrieEmptyKey(t *testing.T) {
+	tr := NewTrie[int]()
+	if _, _, err := tr.LongestPrefixMatch("abc"); err == nil {
+		t.Errorf("expected error from LongestPrefixMatch on an empty Trie")
+	}
+	tr.Insert("", 1)
+	tr.Insert("ab", 2)
+	if key, v, err := tr.LongestPrefixMatch("abc"); err != nil || key != "ab" || v != 2 {
+		t.Errorf("LongestPrefixMatch(\"abc\") = %q, %d, %v", key, v, err)
+	}
+	if key, v, err := tr.LongestPrefixMatch("b"); err != nil || key != "" || v != 1 {
+		t.Errorf("LongestPrefixMatch(\"b\") = %q, %d, %v", key, v, err)
+	}
+	if !tr.Delete("") || tr.Contains("") || tr.Size() != 1 {
+		t.Errorf("failed to delete the empty key")
+	}
+	if tr.CountPrefix("") != 1 || tr.CountPrefix("a") != 1 || tr.CountPrefix("b") != 0 {
+		t.Errorf("CountPrefix wrong after deleting the empty key")
+	}
+}
+
+func TestTrieStringHelpers(t *testing.T) {
+	tr := NewTrie[int]()
+	tr.InsertString("foo", 1)
+	tr.InsertString("foobar", 2)
+	if v, err := tr.GetString("foobar"); err != nil || v != 2 {
+		t.Errorf("GetString(\"foobar\") = %d, %v", v, err)
+	}
+	if key, v, err := tr.LongestPrefixMatchString("food"); err != nil || key != "foo" || v != 1 {
+		t.Errorf("LongestPrefixMatchString(\"food\") = %q, %d, %v", key, v, err)
+	}
+	var keys []String
+	for it := tr.KeysWithPrefixString("fo"); it.HasNext(); {
+		keys = append(keys, it.Next())
+	}
+	if !slices.Equal(keys, []String{"foo", "foobar"}) {
+		t.Errorf("KeysWithPrefixString(\"fo\") = %q", keys)
+	}
+	if !tr.DeleteString("foo") || tr.DeleteString("foo") {
+		t.Errorf("DeleteString did not report presence correctly")
+	}
+	if _, err := tr.GetString("foo"); err == nil {
+		t.Errorf("expected error getting a deleted key")
+	}
+}